@@ -0,0 +1,256 @@
+package cloudhealth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// AssetTypeDescription represents the attributes and associations of an asset type of the Assets API.
+type AssetTypeDescription struct {
+	Name       string           `json:"name"`
+	Attributes []AssetAttribute `json:"attributes"`
+	Relations  []AssetRelation  `json:"relations"`
+}
+
+// AssetAttribute represents a field of an asset type.
+type AssetAttribute struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// AssetRelation represents an association from an asset type to another asset type.
+type AssetRelation struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// AssetCatalog represents the descriptions of asset types, used to validate asset queries offline.
+// It can be saved and loaded as JSON.
+type AssetCatalog struct {
+	Types map[string]*AssetTypeDescription `json:"types"`
+}
+
+// assetTypeCache keeps the asset types fetched by a Client.
+type assetTypeCache struct {
+	mutex        sync.Mutex
+	names        []string
+	descriptions map[string]*AssetTypeDescription
+}
+
+// assetTypes returns the asset type cache of the client.
+func (s *Client) assetTypes() *assetTypeCache {
+	s.assetTypeCacheOnce.Do(func() {
+		s.assetTypeCache = &assetTypeCache{descriptions: make(map[string]*AssetTypeDescription)}
+	})
+
+	return s.assetTypeCache
+}
+
+// GetAssetTypes gets the names of all asset types of the Assets API, sorted. Results are cached by the Client.
+func (s *Client) GetAssetTypes(ctx context.Context) ([]string, error) {
+	cache := s.assetTypes()
+	cache.mutex.Lock()
+	names := cache.names
+	cache.mutex.Unlock()
+
+	if names != nil {
+		return append([]string(nil), names...), nil
+	}
+
+	// Make the API call
+	responseBody, err := getResponsePageWithContext(ctx, s, "api.json")
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the response data into the list of names
+	err = json.Unmarshal(responseBody, &names)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	cache.mutex.Lock()
+	cache.names = names
+	cache.mutex.Unlock()
+
+	return append([]string(nil), names...), nil
+}
+
+// DescribeAssetType gets the attributes and relations of the asset type. Results are cached by the Client.
+func (s *Client) DescribeAssetType(ctx context.Context, assetType string) (*AssetTypeDescription, error) {
+	cache := s.assetTypes()
+	cache.mutex.Lock()
+	cached, ok := cache.descriptions[assetType]
+	cache.mutex.Unlock()
+
+	if ok {
+		return cached.copy(), nil
+	}
+
+	// Set up the URL
+	relativeURL := fmt.Sprintf("api/%s.json", assetType)
+
+	// Make the API call
+	responseBody, err := getResponsePageWithContext(ctx, s, relativeURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the response data into the AssetTypeDescription struct
+	var description AssetTypeDescription
+	err = json.Unmarshal(responseBody, &description)
+	if err != nil {
+		return nil, err
+	}
+	if description.Name == "" {
+		description.Name = assetType
+	}
+
+	cache.mutex.Lock()
+	cache.descriptions[assetType] = &description
+	cache.mutex.Unlock()
+
+	return description.copy(), nil
+}
+
+// copy returns a copy of the description that can be modified without affecting the original.
+func (d *AssetTypeDescription) copy() *AssetTypeDescription {
+	return &AssetTypeDescription{
+		Name:       d.Name,
+		Attributes: append([]AssetAttribute(nil), d.Attributes...),
+		Relations:  append([]AssetRelation(nil), d.Relations...),
+	}
+}
+
+// GetAssetCatalog gets the descriptions of the asset types, or of all asset types when none are specified.
+func (s *Client) GetAssetCatalog(ctx context.Context, assetTypes ...string) (*AssetCatalog, error) {
+	if len(assetTypes) == 0 {
+		var err error
+		assetTypes, err = s.GetAssetTypes(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	catalog := &AssetCatalog{Types: make(map[string]*AssetTypeDescription, len(assetTypes))}
+	for _, assetType := range assetTypes {
+		description, err := s.DescribeAssetType(ctx, assetType)
+		if err != nil {
+			return nil, err
+		}
+		catalog.Types[assetType] = description
+	}
+
+	return catalog, nil
+}
+
+// LoadAssetCatalog reads an AssetCatalog previously written with Save.
+func LoadAssetCatalog(r io.Reader) (*AssetCatalog, error) {
+	var catalog AssetCatalog
+	err := json.NewDecoder(r).Decode(&catalog)
+	if err != nil {
+		return nil, err
+	}
+
+	return &catalog, nil
+}
+
+// Save writes the AssetCatalog as JSON.
+func (c *AssetCatalog) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(c)
+}
+
+// Attribute returns the attribute of the asset type with the given name.
+func (d *AssetTypeDescription) Attribute(name string) (AssetAttribute, bool) {
+	for _, attribute := range d.Attributes {
+		if attribute.Name == name {
+			return attribute, true
+		}
+	}
+
+	return AssetAttribute{}, false
+}
+
+// Relation returns the relation of the asset type with the given name.
+func (d *AssetTypeDescription) Relation(name string) (AssetRelation, bool) {
+	for _, relation := range d.Relations {
+		if relation.Name == name {
+			return relation, true
+		}
+	}
+
+	return AssetRelation{}, false
+}
+
+// ValidateQuery checks that the fields and relations used by the query exist on the asset type,
+// following relations for fields such as `account.name`.
+func (c *AssetCatalog) ValidateQuery(assetType string, query *AssetQuery) error {
+	if query.Err() != nil {
+		return query.Err()
+	}
+
+	if _, ok := c.Types[assetType]; !ok {
+		return fmt.Errorf("unknown asset type `%s`", assetType)
+	}
+
+	var fields []string
+	for _, condition := range query.Conditions {
+		fields = append(fields, condition.Field)
+	}
+	fields = append(fields, query.Fields...)
+
+	for _, field := range fields {
+		err := c.resolve(assetType, field, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, relation := range query.Includes {
+		err := c.resolve(assetType, relation, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolve follows the relations of a dotted path from the asset type and checks that its last
+// element is an attribute, or a relation when isRelation is set.
+func (c *AssetCatalog) resolve(assetType string, path string, isRelation bool) error {
+	parts := strings.Split(path, ".")
+	current := assetType
+
+	for i, part := range parts {
+		description, ok := c.Types[current]
+		if !ok {
+			return fmt.Errorf("asset type `%s` used by `%s` is not in the catalog", current, path)
+		}
+
+		last := i == len(parts)-1
+		if last && !isRelation {
+			if _, ok := description.Attribute(part); !ok {
+				return fmt.Errorf("asset type `%s` has no attribute `%s`", current, part)
+			}
+			return nil
+		}
+
+		relation, ok := description.Relation(part)
+		if !ok {
+			return fmt.Errorf("asset type `%s` has no relation `%s`", current, part)
+		}
+		if last {
+			return nil
+		}
+		current = relation.Type
+	}
+
+	return nil
+}
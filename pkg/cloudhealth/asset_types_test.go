@@ -0,0 +1,85 @@
+package cloudhealth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAssetCatalog(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.EscapedPath() {
+		case "/api.json":
+			w.Write([]byte(`["AwsInstance", "AwsAccount"]`))
+		case "/api/AwsInstance.json":
+			w.Write([]byte(`{"name": "AwsInstance", "attributes": [{"name": "instance_id", "type": "string"}, {"name": "is_active", "type": "boolean"}], "relations": [{"name": "account", "type": "AwsAccount"}]}`))
+		case "/api/AwsAccount.json":
+			w.Write([]byte(`{"name": "AwsAccount", "attributes": [{"name": "name", "type": "string"}], "relations": []}`))
+		default:
+			t.Errorf("Unexpected request to ‘%s’", r.URL.EscapedPath())
+		}
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+
+	catalog, err := c.GetAssetCatalog(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, catalog.Types, 2)
+
+	// Descriptions are cached by the client
+	_, err = c.GetAssetCatalog(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, requests)
+
+	// The catalog survives a round trip
+	var buffer bytes.Buffer
+	assert.NoError(t, catalog.Save(&buffer))
+	catalog, err = LoadAssetCatalog(&buffer)
+	assert.NoError(t, err)
+
+	query := NewAssetQuery().Active(true).Where("account.name", "=", "Production").Include("account").Select("instance_id")
+	assert.NoError(t, catalog.ValidateQuery("AwsInstance", query))
+
+	assert.EqualError(t, catalog.ValidateQuery("AwsInstance", NewAssetQuery().Where("instance_type", "=", "m5.large")), "asset type `AwsInstance` has no attribute `instance_type`")
+	assert.EqualError(t, catalog.ValidateQuery("AwsInstance", NewAssetQuery().Where("owner.name", "=", "x")), "asset type `AwsInstance` has no relation `owner`")
+	assert.EqualError(t, catalog.ValidateQuery("AwsInstance", NewAssetQuery().Include("instance_id")), "asset type `AwsInstance` has no relation `instance_id`")
+	assert.EqualError(t, catalog.ValidateQuery("AwsVolume", NewAssetQuery()), "unknown asset type `AwsVolume`")
+}
+
+func TestGetAssetTypesReturnsCopies(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/api.json":
+			w.Write([]byte(`["AwsInstance", "AwsAccount"]`))
+		case "/api/AwsAccount.json":
+			w.Write([]byte(`{"name": "AwsAccount", "attributes": [{"name": "name", "type": "string"}], "relations": []}`))
+		}
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+
+	names, err := c.GetAssetTypes(context.Background())
+	assert.NoError(t, err)
+	names[0] = "Modified"
+	names, err = c.GetAssetTypes(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"AwsAccount", "AwsInstance"}, names)
+
+	description, err := c.DescribeAssetType(context.Background(), "AwsAccount")
+	assert.NoError(t, err)
+	description.Attributes[0].Name = "modified"
+	description, err = c.DescribeAssetType(context.Background(), "AwsAccount")
+	assert.NoError(t, err)
+	assert.Equal(t, "name", description.Attributes[0].Name)
+}
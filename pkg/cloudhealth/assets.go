@@ -0,0 +1,219 @@
+package cloudhealth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// assetSearchPageSize is the number of assets requested per page of an asset search.
+const assetSearchPageSize = 100
+
+// assetFieldPattern matches field names, optionally prefixed by relations, such as `account.name`.
+var assetFieldPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// assetQueryOperators lists the comparison operators of CloudHealth's asset query language.
+var assetQueryOperators = map[string]bool{
+	"=":  true,
+	"!=": true,
+	">":  true,
+	">=": true,
+	"<":  true,
+	"<=": true,
+	"~":  true,
+}
+
+// Asset represents an asset returned by the Assets API, keyed by field name.
+type Asset map[string]interface{}
+
+// AssetCondition represents a field comparison of an asset query.
+type AssetCondition struct {
+	Field    string
+	Operator string
+	Value    interface{}
+}
+
+// AssetQuery builds a query in CloudHealth's asset query language. Methods can be chained and
+// the first error found is returned when the query is used.
+type AssetQuery struct {
+	Conditions []AssetCondition
+	Includes   []string
+	Fields     []string
+	err        error
+}
+
+// NewAssetQuery returns an empty AssetQuery, matching all assets.
+func NewAssetQuery() *AssetQuery {
+	return &AssetQuery{}
+}
+
+// Where adds a comparison of a field to a value, such as Where("instance_type", "=", "m5.large").
+// Fields of related assets are prefixed with the relation, such as `account.name`.
+func (q *AssetQuery) Where(field string, operator string, value interface{}) *AssetQuery {
+	if q.err == nil && !assetFieldPattern.MatchString(field) {
+		q.err = fmt.Errorf("invalid asset field `%s`", field)
+	}
+	if q.err == nil && !assetQueryOperators[operator] {
+		q.err = fmt.Errorf("invalid asset query operator `%s` for field `%s`", operator, field)
+	}
+
+	q.Conditions = append(q.Conditions, AssetCondition{Field: field, Operator: operator, Value: value})
+	return q
+}
+
+// Active restricts the query to active or inactive assets.
+func (q *AssetQuery) Active(active bool) *AssetQuery {
+	return q.Where("is_active", "=", active)
+}
+
+// Include requests related assets, such as the `account` of an AwsInstance, to be returned with each asset.
+func (q *AssetQuery) Include(relations ...string) *AssetQuery {
+	for _, relation := range relations {
+		if q.err == nil && !assetFieldPattern.MatchString(relation) {
+			q.err = fmt.Errorf("invalid asset relation `%s`", relation)
+		}
+	}
+
+	q.Includes = append(q.Includes, relations...)
+	return q
+}
+
+// Select restricts the fields returned for each asset.
+func (q *AssetQuery) Select(fields ...string) *AssetQuery {
+	for _, field := range fields {
+		if q.err == nil && !assetFieldPattern.MatchString(field) {
+			q.err = fmt.Errorf("invalid asset field `%s`", field)
+		}
+	}
+
+	q.Fields = append(q.Fields, fields...)
+	return q
+}
+
+// Err returns the first error found while building the query.
+func (q *AssetQuery) Err() error {
+	return q.err
+}
+
+// String returns the query expression, such as `is_active=1 and instance_type='m5.large'`.
+func (q *AssetQuery) String() string {
+	conditions := make([]string, len(q.Conditions))
+	for i, condition := range q.Conditions {
+		conditions[i] = condition.Field + condition.Operator + formatAssetQueryValue(condition.Value)
+	}
+
+	return strings.Join(conditions, " and ")
+}
+
+// assetQueryEscaper escapes backslashes, then quotes, in strings of the asset query language.
+var assetQueryEscaper = strings.NewReplacer(`\`, `\\`, "'", `\'`)
+
+// formatAssetQueryValue formats a value for the asset query language.
+func formatAssetQueryValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return "'" + v.UTC().Format(time.RFC3339) + "'"
+	default:
+		return "'" + assetQueryEscaper.Replace(fmt.Sprint(v)) + "'"
+	}
+}
+
+// SearchAssets gets all assets of the asset type, such as AwsInstance, matching the query. A nil query matches all assets.
+func (s *Client) SearchAssets(ctx context.Context, assetType string, query *AssetQuery) ([]Asset, error) {
+	if query == nil {
+		query = NewAssetQuery()
+	}
+	if query.Err() != nil {
+		return nil, query.Err()
+	}
+
+	// Set variables we will need along the way
+	var assets []Asset
+	var page, pageSize int = 1, assetSearchPageSize
+
+	// Loop for paging
+	for {
+		// Set up the query parameters for the API
+		params := url.Values{
+			"api_version": {"2"},
+			"name":        {assetType},
+			"page":        {strconv.Itoa(page)},
+			"per_page":    {strconv.Itoa(pageSize)},
+		}
+		if len(query.Conditions) > 0 {
+			params.Set("query", query.String())
+		}
+		if len(query.Includes) > 0 {
+			params.Set("include", strings.Join(query.Includes, ","))
+		}
+		if len(query.Fields) > 0 {
+			params.Set("fields", strings.Join(query.Fields, ","))
+		}
+
+		// Set up the URL
+		relativeURL := fmt.Sprintf("api/search.json?%s", params.Encode())
+
+		// Make the API call
+		responseBody, err := getResponsePageWithContext(ctx, s, relativeURL)
+		if err != nil {
+			return nil, err
+		}
+
+		// Unmarshal the response data into the page of assets
+		var pageAssets []Asset
+		err = json.Unmarshal(responseBody, &pageAssets)
+		if err != nil {
+			return nil, err
+		}
+		assets = append(assets, pageAssets...)
+
+		// Check length of the page to determine if we should break out of the loop
+		if len(pageAssets) < pageSize {
+			break
+		}
+
+		// Increment page counter
+		page++
+	}
+
+	return assets, nil
+}
+
+// SearchAssetsInto gets all assets of the asset type matching the query and decodes them into out,
+// which must be a pointer to a slice of structs with `json` tags.
+func (s *Client) SearchAssetsInto(ctx context.Context, assetType string, query *AssetQuery, out interface{}) error {
+	assets, err := s.SearchAssets(ctx, assetType, query)
+	if err != nil {
+		return err
+	}
+
+	return DecodeAssets(assets, out)
+}
+
+// DecodeAssets decodes assets into out, which must be a pointer to a slice of structs with `json` tags.
+func DecodeAssets(assets []Asset, out interface{}) error {
+	encoded, err := json.Marshal(assets)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(encoded, out)
+}
@@ -0,0 +1,101 @@
+package cloudhealth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssetQuery(t *testing.T) {
+	query := NewAssetQuery().Active(true).Where("instance_type", "=", "m5.large").Where("account.name", "~", "it's").Include("account")
+	assert.NoError(t, query.Err())
+	assert.Equal(t, `is_active=1 and instance_type='m5.large' and account.name~'it\'s'`, query.String())
+
+	// Backslashes are escaped so that they can't cancel the escaping of quotes
+	query = NewAssetQuery().Where("name", "=", `a\' or is_active=0 or name='`)
+	assert.Equal(t, `name='a\\\' or is_active=0 or name=\''`, query.String())
+
+	query = NewAssetQuery().Where("a", "=", int8(1)).Where("b", "=", uint16(2)).Where("c", "=", int32(-3)).Where("d", "=", uint64(4)).Where("e", "=", float32(0.5))
+	assert.Equal(t, `a=1 and b=2 and c=-3 and d=4 and e=0.5`, query.String())
+
+	assert.EqualError(t, NewAssetQuery().Where("name; drop", "=", 1).Err(), "invalid asset field `name; drop`")
+	assert.EqualError(t, NewAssetQuery().Where("name", "like", 1).Err(), "invalid asset query operator `like` for field `name`")
+}
+
+func TestSearchAssets(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expectedURL := "/api/search.json"
+		if r.URL.EscapedPath() != expectedURL {
+			t.Errorf("Expected request to ‘%s’, got ‘%s’", expectedURL, r.URL.EscapedPath())
+		}
+		assert.Equal(t, "AwsInstance", r.URL.Query().Get("name"))
+		assert.Equal(t, "is_active=1", r.URL.Query().Get("query"))
+		assert.Equal(t, "account", r.URL.Query().Get("include"))
+
+		// Return a full first page and a partial second page
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		count := assetSearchPageSize
+		if page > 1 {
+			count = 1
+		}
+		assets := make([]Asset, count)
+		for i := range assets {
+			assets[i] = Asset{"instance_id": fmt.Sprintf("i-%d-%d", page, i), "account": map[string]interface{}{"name": "Production"}}
+		}
+		body, _ := json.Marshal(assets)
+		w.Write(body)
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+
+	var instances []struct {
+		InstanceID string `json:"instance_id"`
+		Account    struct {
+			Name string `json:"name"`
+		} `json:"account"`
+	}
+	err = c.SearchAssetsInto(context.Background(), "AwsInstance", NewAssetQuery().Active(true).Include("account"), &instances)
+	assert.NoError(t, err)
+	assert.Len(t, instances, assetSearchPageSize+1)
+	assert.Equal(t, "i-2-0", instances[assetSearchPageSize].InstanceID)
+	assert.Equal(t, "Production", instances[0].Account.Name)
+}
+
+func TestSearchAwsInstances(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, AssetTypeAwsInstance, r.URL.Query().Get("name"))
+		assert.Equal(t, "account", r.URL.Query().Get("include"))
+
+		w.Write([]byte(`[{"id": 1, "instance_id": "i-0123", "instance_type": "m5.large", "is_active": true, "launch_date": "2021-06-01T12:00:00Z", "account": {"id": 7, "name": "Production", "owner_id": "123456789012"}}, {"id": 2, "instance_id": "i-4567", "launch_date": ""}, {"id": 3, "instance_id": "i-8901", "launch_date": "unknown"}]`))
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+
+	query := NewAssetQuery().Active(true)
+	instances, err := c.SearchAwsInstances(context.Background(), query)
+	assert.NoError(t, err)
+	assert.Empty(t, query.Includes)
+
+	if assert.Len(t, instances, 3) {
+		assert.Equal(t, "i-0123", instances[0].InstanceID)
+		assert.Equal(t, "m5.large", instances[0].InstanceType)
+		assert.Equal(t, 2021, instances[0].LaunchDate.Year())
+		if assert.NotNil(t, instances[0].Account) {
+			assert.Equal(t, "123456789012", instances[0].Account.OwnerID)
+		}
+
+		// Missing or invalid dates don't fail the search
+		assert.True(t, instances[1].LaunchDate.IsZero())
+		assert.True(t, instances[2].LaunchDate.IsZero())
+	}
+}
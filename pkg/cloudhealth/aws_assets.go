@@ -0,0 +1,193 @@
+package cloudhealth
+
+import (
+	"context"
+)
+
+// Asset types of the Assets API with typed models.
+const (
+	AssetTypeAwsAccount        = "AwsAccount"
+	AssetTypeAwsInstance       = "AwsInstance"
+	AssetTypeAwsVolume         = "AwsVolume"
+	AssetTypeAwsRdsInstance    = "AwsRdsInstance"
+	AssetTypeAwsS3Bucket       = "AwsS3Bucket"
+	AssetTypeAwsLoadBalancer   = "AwsLoadBalancer"
+	AssetTypeAwsLambdaFunction = "AwsLambdaFunction"
+)
+
+// AwsAccountAsset represents the AwsAccount an asset belongs to, as returned by the Assets API.
+type AwsAccountAsset struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	OwnerID     string `json:"owner_id"`
+	AmazonName  string `json:"amazon_name,omitempty"`
+	AccountType string `json:"account_type,omitempty"`
+}
+
+// AwsInstance represents an EC2 instance.
+type AwsInstance struct {
+	ID           int              `json:"id"`
+	InstanceID   string           `json:"instance_id"`
+	Name         string           `json:"name"`
+	InstanceType string           `json:"instance_type"`
+	State        string           `json:"state"`
+	Platform     string           `json:"platform,omitempty"`
+	VpcID        string           `json:"vpc_id,omitempty"`
+	PrivateIP    string           `json:"private_ip,omitempty"`
+	PublicIP     string           `json:"public_ip,omitempty"`
+	Reserved     bool             `json:"reserved"`
+	IsActive     bool             `json:"is_active"`
+	LaunchDate   Timestamp        `json:"launch_date"`
+	Account      *AwsAccountAsset `json:"account,omitempty"`
+}
+
+// AwsVolume represents an EBS volume.
+type AwsVolume struct {
+	ID         int              `json:"id"`
+	VolumeID   string           `json:"volume_id"`
+	Name       string           `json:"name"`
+	VolumeType string           `json:"volume_type"`
+	Size       int              `json:"size"`
+	Iops       int              `json:"iops,omitempty"`
+	Encrypted  bool             `json:"encrypted"`
+	State      string           `json:"state"`
+	IsActive   bool             `json:"is_active"`
+	CreatedAt  Timestamp        `json:"created_at"`
+	Account    *AwsAccountAsset `json:"account,omitempty"`
+}
+
+// AwsRdsInstance represents an RDS database instance.
+type AwsRdsInstance struct {
+	ID               int              `json:"id"`
+	InstanceID       string           `json:"instance_id"`
+	InstanceType     string           `json:"instance_type"`
+	Engine           string           `json:"engine"`
+	EngineVersion    string           `json:"engine_version"`
+	MultiAZ          bool             `json:"multi_az"`
+	AllocatedStorage int              `json:"allocated_storage"`
+	State            string           `json:"state"`
+	IsActive         bool             `json:"is_active"`
+	CreatedAt        Timestamp        `json:"created_at"`
+	Account          *AwsAccountAsset `json:"account,omitempty"`
+}
+
+// AwsS3Bucket represents an S3 bucket.
+type AwsS3Bucket struct {
+	ID          int              `json:"id"`
+	Name        string           `json:"name"`
+	Region      string           `json:"region,omitempty"`
+	SizeInBytes int64            `json:"size_in_bytes"`
+	ObjectCount int64            `json:"object_count"`
+	IsActive    bool             `json:"is_active"`
+	CreatedAt   Timestamp        `json:"created_at"`
+	Account     *AwsAccountAsset `json:"account,omitempty"`
+}
+
+// AwsLoadBalancer represents a classic, application or network load balancer.
+type AwsLoadBalancer struct {
+	ID               int              `json:"id"`
+	Name             string           `json:"name"`
+	DNSName          string           `json:"dns_name"`
+	LoadBalancerType string           `json:"load_balancer_type,omitempty"`
+	Scheme           string           `json:"scheme,omitempty"`
+	VpcID            string           `json:"vpc_id,omitempty"`
+	IsActive         bool             `json:"is_active"`
+	CreatedAt        Timestamp        `json:"created_at"`
+	Account          *AwsAccountAsset `json:"account,omitempty"`
+}
+
+// AwsLambdaFunction represents a Lambda function.
+type AwsLambdaFunction struct {
+	ID           int              `json:"id"`
+	Name         string           `json:"name"`
+	Arn          string           `json:"arn"`
+	Runtime      string           `json:"runtime"`
+	MemorySize   int              `json:"memory_size"`
+	Timeout      int              `json:"timeout"`
+	IsActive     bool             `json:"is_active"`
+	LastModified Timestamp        `json:"last_modified"`
+	Account      *AwsAccountAsset `json:"account,omitempty"`
+}
+
+// withAccount returns a copy of the query also including the `account` relation of the assets.
+func withAccount(query *AssetQuery) *AssetQuery {
+	if query == nil {
+		query = NewAssetQuery()
+	}
+
+	for _, relation := range query.Includes {
+		if relation == "account" {
+			return query
+		}
+	}
+
+	included := *query
+	included.Includes = append(append([]string{}, query.Includes...), "account")
+	return &included
+}
+
+// SearchAwsInstances gets all EC2 instances matching the query, with their account. A nil query matches all instances.
+func (s *Client) SearchAwsInstances(ctx context.Context, query *AssetQuery) ([]AwsInstance, error) {
+	var instances []AwsInstance
+	err := s.SearchAssetsInto(ctx, AssetTypeAwsInstance, withAccount(query), &instances)
+	if err != nil {
+		return nil, err
+	}
+
+	return instances, nil
+}
+
+// SearchAwsVolumes gets all EBS volumes matching the query, with their account. A nil query matches all volumes.
+func (s *Client) SearchAwsVolumes(ctx context.Context, query *AssetQuery) ([]AwsVolume, error) {
+	var volumes []AwsVolume
+	err := s.SearchAssetsInto(ctx, AssetTypeAwsVolume, withAccount(query), &volumes)
+	if err != nil {
+		return nil, err
+	}
+
+	return volumes, nil
+}
+
+// SearchAwsRdsInstances gets all RDS instances matching the query, with their account. A nil query matches all instances.
+func (s *Client) SearchAwsRdsInstances(ctx context.Context, query *AssetQuery) ([]AwsRdsInstance, error) {
+	var instances []AwsRdsInstance
+	err := s.SearchAssetsInto(ctx, AssetTypeAwsRdsInstance, withAccount(query), &instances)
+	if err != nil {
+		return nil, err
+	}
+
+	return instances, nil
+}
+
+// SearchAwsS3Buckets gets all S3 buckets matching the query, with their account. A nil query matches all buckets.
+func (s *Client) SearchAwsS3Buckets(ctx context.Context, query *AssetQuery) ([]AwsS3Bucket, error) {
+	var buckets []AwsS3Bucket
+	err := s.SearchAssetsInto(ctx, AssetTypeAwsS3Bucket, withAccount(query), &buckets)
+	if err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+// SearchAwsLoadBalancers gets all load balancers matching the query, with their account. A nil query matches all load balancers.
+func (s *Client) SearchAwsLoadBalancers(ctx context.Context, query *AssetQuery) ([]AwsLoadBalancer, error) {
+	var loadBalancers []AwsLoadBalancer
+	err := s.SearchAssetsInto(ctx, AssetTypeAwsLoadBalancer, withAccount(query), &loadBalancers)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadBalancers, nil
+}
+
+// SearchAwsLambdaFunctions gets all Lambda functions matching the query, with their account. A nil query matches all functions.
+func (s *Client) SearchAwsLambdaFunctions(ctx context.Context, query *AssetQuery) ([]AwsLambdaFunction, error) {
+	var functions []AwsLambdaFunction
+	err := s.SearchAssetsInto(ctx, AssetTypeAwsLambdaFunction, withAccount(query), &functions)
+	if err != nil {
+		return nil, err
+	}
+
+	return functions, nil
+}
@@ -0,0 +1,50 @@
+package cloudhealth
+
+import "context"
+
+// OLAPReportAzureCostHistory is the path of the Azure cost history OLAP report.
+const OLAPReportAzureCostHistory = "azure_cost/history"
+
+// Dimensions available in Azure cost reports.
+const (
+	DimensionAzureService       = "Azure-Service"
+	DimensionAzureSubscription  = "Azure-Subscription"
+	DimensionAzureResourceGroup = "Azure-Resource-Group"
+	DimensionAzureRegion        = "Azure-Region"
+)
+
+// AzureCostHistoryReport represents the details of a Cost History Report for Azure in CloudHealth.
+type AzureCostHistoryReport struct {
+	OLAPReport
+}
+
+// GetAzureCostHistoryReport gets the Azure Cost History Report. The cost measure and the
+// Azure-Service dimension are used when no measures or dimensions are specified.
+func (s *Client) GetAzureCostHistoryReport(requestOptions *OLAPReportRequestOptions) (*AzureCostHistoryReport, error) {
+	return s.GetAzureCostHistoryReportWithContext(context.Background(), requestOptions)
+}
+
+// GetAzureCostHistoryReportWithContext gets the Azure Cost History Report, bound to the given context.
+func (s *Client) GetAzureCostHistoryReportWithContext(ctx context.Context, requestOptions *OLAPReportRequestOptions) (*AzureCostHistoryReport, error) {
+	report, err := s.GetOLAPReportWithContext(ctx, OLAPReportAzureCostHistory, withCostHistoryDefaults(requestOptions, DimensionAzureService))
+	if err != nil {
+		return nil, err
+	}
+
+	return &AzureCostHistoryReport{OLAPReport: *report}, nil
+}
+
+// Services returns the Azure services of the report, if grouped by service.
+func (r *AzureCostHistoryReport) Services() []OLAPDimensionMember {
+	return r.dimensionMembers(DimensionAzureService)
+}
+
+// Subscriptions returns the Azure subscriptions of the report, if grouped by subscription.
+func (r *AzureCostHistoryReport) Subscriptions() []OLAPDimensionMember {
+	return r.dimensionMembers(DimensionAzureSubscription)
+}
+
+// ResourceGroups returns the Azure resource groups of the report, if grouped by resource group.
+func (r *AzureCostHistoryReport) ResourceGroups() []OLAPDimensionMember {
+	return r.dimensionMembers(DimensionAzureResourceGroup)
+}
@@ -0,0 +1,133 @@
+package cloudhealth
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// BillDropInfo represents a bill drop: a delivery of billing data from a cloud provider to CloudHealth.
+// DataThrough is the last instant covered by the delivered data, and Final is set once the bill
+// for the billing period (YYYY-MM) is closed. Dates CloudHealth leaves empty are zero.
+type BillDropInfo struct {
+	Cloud         string    `json:"cloud"`
+	BillingPeriod string    `json:"billing_period"`
+	LastBillDrop  Timestamp `json:"last_bill_drop"`
+	DataThrough   Timestamp `json:"data_through"`
+	Final         bool      `json:"final"`
+}
+
+// BillDrops represents the bill drops a report was computed from.
+type BillDrops []BillDropInfo
+
+// ForCloud returns the bill drops of the cloud provider.
+func (b BillDrops) ForCloud(cloud CloudProvider) BillDrops {
+	var drops BillDrops
+	for _, drop := range b {
+		if strings.EqualFold(drop.Cloud, string(cloud)) {
+			drops = append(drops, drop)
+		}
+	}
+
+	return drops
+}
+
+// CoveredThrough returns the latest instant covered by any bill drop of the cloud provider, or the zero
+// time when there are none.
+func (b BillDrops) CoveredThrough(cloud CloudProvider) time.Time {
+	var latest time.Time
+	for _, drop := range b.ForCloud(cloud) {
+		if drop.DataThrough.After(latest) {
+			latest = drop.DataThrough.Time
+		}
+	}
+
+	return latest
+}
+
+// Covers reports whether the bill drops of the cloud provider include data for the whole day of date.
+func (b BillDrops) Covers(cloud CloudProvider, date time.Time) bool {
+	date = date.UTC()
+	endOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+
+	return !b.CoveredThrough(cloud).Before(endOfDay.Add(-time.Nanosecond))
+}
+
+// IsPeriodFinal reports whether the data of the cloud provider for the month containing period is final,
+// that is whether the bill drop of that month is marked as final. Drops of later months say nothing about
+// it: the bill of a month can still change after data for the next one starts arriving.
+func (b BillDrops) IsPeriodFinal(cloud CloudProvider, period time.Time) bool {
+	month := period.UTC().Format("2006-01")
+
+	for _, drop := range b.ForCloud(cloud) {
+		if drop.BillingPeriod == month && drop.Final {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsPeriodFinal reports whether the AWS data of the report for the month containing period is final.
+func (r *AWSCostHistoryReport) IsPeriodFinal(period time.Time) bool {
+	return r.BillDropInfo.IsPeriodFinal(ProviderAWS, period)
+}
+
+// IsPeriodFinal reports whether the data of the cloud provider in the report for the month containing period is final.
+func (r *OLAPReport) IsPeriodFinal(cloud CloudProvider, period time.Time) bool {
+	return r.BillDropInfo.IsPeriodFinal(cloud, period)
+}
+
+// GetBillDrops gets the latest bill drops known to CloudHealth. It never reads the report cache.
+func (s *Client) GetBillDrops(ctx context.Context) (BillDrops, error) {
+	// The smallest possible report is enough to read the bill drops
+	report, err := s.getOLAPReport(ctx, OLAPReportCostHistory, &OLAPReportRequestOptions{
+		Interval:  IntervalMonthly,
+		TimeRange: TimeIndices(0),
+		Measures:  []string{MeasureCost},
+	}, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return report.BillDropInfo, nil
+}
+
+// WaitForBillDrop polls CloudHealth every pollInterval until the bill drops of the cloud provider cover
+// the whole day of date, or the context is done.
+func (s *Client) WaitForBillDrop(ctx context.Context, cloud CloudProvider, date time.Time, pollInterval time.Duration) (BillDrops, error) {
+	return s.waitForBillDrops(ctx, pollInterval, func(drops BillDrops) bool {
+		return drops.Covers(cloud, date)
+	})
+}
+
+// WaitForFinalPeriod polls CloudHealth every pollInterval until the data of the cloud provider for the
+// month containing period is final, or the context is done.
+func (s *Client) WaitForFinalPeriod(ctx context.Context, cloud CloudProvider, period time.Time, pollInterval time.Duration) (BillDrops, error) {
+	return s.waitForBillDrops(ctx, pollInterval, func(drops BillDrops) bool {
+		return drops.IsPeriodFinal(cloud, period)
+	})
+}
+
+// waitForBillDrops polls the bill drops until done returns true or the context is done.
+func (s *Client) waitForBillDrops(ctx context.Context, pollInterval time.Duration, done func(BillDrops) bool) (BillDrops, error) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		drops, err := s.GetBillDrops(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if done(drops) {
+			return drops, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
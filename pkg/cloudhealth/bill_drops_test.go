@@ -0,0 +1,77 @@
+package cloudhealth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBillDrops(t *testing.T) {
+	drops := BillDrops{
+		{Cloud: "AWS", BillingPeriod: "2022-01", Final: true, DataThrough: NewTimestamp(time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond))},
+		{Cloud: "AWS", BillingPeriod: "2022-02", DataThrough: NewTimestamp(time.Date(2022, 2, 10, 6, 0, 0, 0, time.UTC))},
+		{Cloud: "Azure", BillingPeriod: "2022-01", DataThrough: NewTimestamp(time.Date(2022, 1, 20, 0, 0, 0, 0, time.UTC))},
+	}
+
+	assert.True(t, drops.IsPeriodFinal(ProviderAWS, time.Date(2022, 1, 15, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, drops.IsPeriodFinal(ProviderAWS, time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)))
+
+	// Drops of later months don't make a month final
+	assert.False(t, drops.IsPeriodFinal(ProviderAWS, time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, drops.IsPeriodFinal(ProviderAzure, time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)))
+
+	// AWS bill drops say nothing about other clouds
+	assert.False(t, drops.IsPeriodFinal(ProviderAzure, time.Date(2022, 1, 15, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, drops.IsPeriodFinal(ProviderGCP, time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)))
+
+	assert.Equal(t, time.Date(2022, 2, 10, 6, 0, 0, 0, time.UTC), drops.CoveredThrough(ProviderAWS))
+	assert.True(t, drops.Covers(ProviderAWS, time.Date(2022, 2, 9, 12, 0, 0, 0, time.UTC)))
+	assert.False(t, drops.Covers(ProviderAWS, time.Date(2022, 2, 10, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, drops.Covers(ProviderAzure, time.Date(2022, 2, 9, 12, 0, 0, 0, time.UTC)))
+}
+
+func TestBillDropInfoLenientDates(t *testing.T) {
+	var report OLAPReport
+	err := json.Unmarshal([]byte(`{"bill_drop_info": [
+		{"cloud": "AWS", "billing_period": "2022-02", "last_bill_drop": "", "data_through": "2022-02-10 06:00:00 UTC"},
+		{"cloud": "GCP", "billing_period": "2022-02", "last_bill_drop": null, "data_through": "soon"}
+	]}`), &report)
+	assert.NoError(t, err)
+	assert.True(t, report.BillDropInfo[0].LastBillDrop.IsZero())
+	assert.Equal(t, time.Date(2022, 2, 10, 6, 0, 0, 0, time.UTC), report.BillDropInfo.CoveredThrough(ProviderAWS))
+	assert.True(t, report.BillDropInfo.CoveredThrough(ProviderGCP).IsZero())
+
+	encoded, err := json.Marshal(report.BillDropInfo[1])
+	assert.NoError(t, err)
+	assert.Contains(t, string(encoded), `"data_through":null`)
+}
+
+func TestWaitForBillDrop(t *testing.T) {
+	polls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		dataThrough := "2022-02-09T06:00:00Z"
+		if polls > 1 {
+			dataThrough = "2022-02-11T06:00:00Z"
+		}
+		fmt.Fprintf(w, `{"bill_drop_info": [{"cloud": "AWS", "billing_period": "2022-02", "data_through": "%s"}]}`, dataThrough)
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	drops, err := c.WaitForBillDrop(ctx, ProviderAWS, time.Date(2022, 2, 10, 0, 0, 0, 0, time.UTC), 10*time.Millisecond)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, polls)
+	assert.Equal(t, "2022-02", drops[0].BillingPeriod)
+}
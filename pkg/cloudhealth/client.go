@@ -1,10 +1,23 @@
 // Package cloudhealth is a wrapper for the CloudHealth API.
 package cloudhealth
 
+import (
+	"sync"
+)
+
+// DefaultGraphQLEndpointURL is the CloudHealth GraphQL endpoint used for FlexReports.
+const DefaultGraphQLEndpointURL = "https://apps.cloudhealthtech.com/graphql"
+
 // Client communicates with the CloudHealth API.
 type Client struct {
-	APIKey      string
-	EndpointURL string
+	APIKey             string
+	EndpointURL        string
+	GraphQLEndpointURL string
+	ReportPolling      ReportPolling
+	ReportCaching      ReportCaching
+
+	assetTypeCacheOnce sync.Once
+	assetTypeCache     *assetTypeCache
 }
 
 // NewClient returns a new CloudHealth.Client for accessing the CloudHealth API.
@@ -14,5 +27,6 @@ func NewClient(apiKey string, defaultEndpointURL string) (*Client, error) {
 	}
 
 	s.EndpointURL = defaultEndpointURL
+	s.GraphQLEndpointURL = DefaultGraphQLEndpointURL
 	return s, nil
 }
@@ -0,0 +1,255 @@
+package cloudhealth
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Asset types of commitments.
+const (
+	AssetTypeAwsReservedInstance = "AwsReservedInstance"
+	AssetTypeAwsSavingsPlan      = "AwsSavingsPlan"
+)
+
+// OLAP reports of commitment utilization and coverage, relative to `olap_reports/`.
+const (
+	OLAPReportReservationUtilization = "ri_utilization/history"
+	OLAPReportSavingsPlanUtilization = "savings_plan_utilization/history"
+)
+
+// Measures available in commitment utilization reports, in percent.
+const (
+	MeasureUtilization = "utilization"
+	MeasureCoverage    = "coverage"
+)
+
+// CommitmentKind is the kind of a commitment.
+type CommitmentKind string
+
+// Kinds of commitments.
+const (
+	CommitmentReservedInstance CommitmentKind = "reserved_instance"
+	CommitmentSavingsPlan      CommitmentKind = "savings_plan"
+)
+
+// AwsReservedInstance represents an EC2 Reserved Instance, as returned by the Assets API.
+// Duration is the term in seconds, and FixedPrice and UsagePrice the upfront and hourly prices of each instance.
+type AwsReservedInstance struct {
+	ID                 int              `json:"id"`
+	ReservationID      string           `json:"reservation_id"`
+	InstanceType       string           `json:"instance_type"`
+	InstanceCount      int              `json:"instance_count"`
+	ProductDescription string           `json:"product_description"`
+	AvailabilityZone   string           `json:"availability_zone,omitempty"`
+	Region             string           `json:"region"`
+	OfferingType       string           `json:"offering_type"`
+	State              string           `json:"state"`
+	Start              Timestamp        `json:"start"`
+	End                Timestamp        `json:"end"`
+	Duration           int64            `json:"duration"`
+	FixedPrice         float64          `json:"fixed_price"`
+	UsagePrice         float64          `json:"usage_price"`
+	Utilization        float64          `json:"utilization"`
+	Account            *AwsAccountAsset `json:"account,omitempty"`
+}
+
+// AwsSavingsPlan represents a Savings Plan, as returned by the Assets API.
+// HourlyCommitment is the hourly amount committed to and TermDuration the term in seconds.
+type AwsSavingsPlan struct {
+	ID               int              `json:"id"`
+	SavingsPlanID    string           `json:"savings_plan_id"`
+	SavingsPlanType  string           `json:"savings_plan_type"`
+	PaymentOption    string           `json:"payment_option"`
+	Region           string           `json:"region,omitempty"`
+	State            string           `json:"state"`
+	Start            Timestamp        `json:"start"`
+	End              Timestamp        `json:"end"`
+	TermDuration     int64            `json:"term_duration"`
+	HourlyCommitment float64          `json:"commitment"`
+	Utilization      float64          `json:"utilization"`
+	Account          *AwsAccountAsset `json:"account,omitempty"`
+}
+
+// Commitment represents a Reserved Instance or a Savings Plan in a common shape, such as a record of a local export.
+// HourlyCommitment is the amount paid per hour of the term, upfront payments included, and Utilization is in percent.
+type Commitment struct {
+	Kind             CommitmentKind `json:"kind"`
+	ID               string         `json:"id"`
+	AccountID        string         `json:"account_id"`
+	AccountName      string         `json:"account_name"`
+	Type             string         `json:"type"`
+	Region           string         `json:"region"`
+	Count            int            `json:"count"`
+	Start            time.Time      `json:"start"`
+	End              time.Time      `json:"end"`
+	HourlyCommitment float64        `json:"hourly_commitment"`
+	Utilization      float64        `json:"utilization"`
+}
+
+// CommitmentUtilization represents the utilization and coverage of commitments over a period, in percent.
+type CommitmentUtilization struct {
+	Period      string
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	Utilization float64
+	Coverage    float64
+}
+
+// Commitment returns the Reserved Instance as a Commitment.
+func (r AwsReservedInstance) Commitment() Commitment {
+	commitment := Commitment{
+		Kind:             CommitmentReservedInstance,
+		ID:               r.ReservationID,
+		Type:             r.InstanceType,
+		Region:           r.Region,
+		Count:            r.InstanceCount,
+		Start:            r.Start.Time,
+		End:              r.End.Time,
+		HourlyCommitment: r.UsagePrice * float64(r.InstanceCount),
+		Utilization:      r.Utilization,
+	}
+	if hours := float64(r.Duration) / 3600; hours > 0 {
+		commitment.HourlyCommitment += r.FixedPrice / hours * float64(r.InstanceCount)
+	}
+	if r.Account != nil {
+		commitment.AccountID = r.Account.OwnerID
+		commitment.AccountName = r.Account.Name
+	}
+
+	return commitment
+}
+
+// Commitment returns the Savings Plan as a Commitment.
+func (p AwsSavingsPlan) Commitment() Commitment {
+	commitment := Commitment{
+		Kind:             CommitmentSavingsPlan,
+		ID:               p.SavingsPlanID,
+		Type:             p.SavingsPlanType,
+		Region:           p.Region,
+		Count:            1,
+		Start:            p.Start.Time,
+		End:              p.End.Time,
+		HourlyCommitment: p.HourlyCommitment,
+		Utilization:      p.Utilization,
+	}
+	if p.Account != nil {
+		commitment.AccountID = p.Account.OwnerID
+		commitment.AccountName = p.Account.Name
+	}
+
+	return commitment
+}
+
+// TermMonths returns the length of the term of the commitment, rounded to whole months.
+func (c Commitment) TermMonths() int {
+	return int(math.Round(c.End.Sub(c.Start).Hours() / (24 * 365.25 / 12)))
+}
+
+// DaysUntilExpiry returns the number of whole days left before the commitment expires, negative once expired.
+func (c Commitment) DaysUntilExpiry(now time.Time) int {
+	return int(math.Floor(c.End.Sub(now).Hours() / 24))
+}
+
+// SearchAwsReservedInstances gets all Reserved Instances matching the query, with their account. A nil query matches all Reserved Instances.
+func (s *Client) SearchAwsReservedInstances(ctx context.Context, query *AssetQuery) ([]AwsReservedInstance, error) {
+	var reservedInstances []AwsReservedInstance
+	err := s.SearchAssetsInto(ctx, AssetTypeAwsReservedInstance, withAccount(query), &reservedInstances)
+	if err != nil {
+		return nil, err
+	}
+
+	return reservedInstances, nil
+}
+
+// SearchAwsSavingsPlans gets all Savings Plans matching the query, with their account. A nil query matches all Savings Plans.
+func (s *Client) SearchAwsSavingsPlans(ctx context.Context, query *AssetQuery) ([]AwsSavingsPlan, error) {
+	var savingsPlans []AwsSavingsPlan
+	err := s.SearchAssetsInto(ctx, AssetTypeAwsSavingsPlan, withAccount(query), &savingsPlans)
+	if err != nil {
+		return nil, err
+	}
+
+	return savingsPlans, nil
+}
+
+// GetCommitments gets all active Reserved Instances and Savings Plans as Commitments.
+func (s *Client) GetCommitments(ctx context.Context) ([]Commitment, error) {
+	reservedInstances, err := s.SearchAwsReservedInstances(ctx, NewAssetQuery().Active(true))
+	if err != nil {
+		return nil, err
+	}
+
+	savingsPlans, err := s.SearchAwsSavingsPlans(ctx, NewAssetQuery().Active(true))
+	if err != nil {
+		return nil, err
+	}
+
+	commitments := make([]Commitment, 0, len(reservedInstances)+len(savingsPlans))
+	for _, reservedInstance := range reservedInstances {
+		commitments = append(commitments, reservedInstance.Commitment())
+	}
+	for _, savingsPlan := range savingsPlans {
+		commitments = append(commitments, savingsPlan.Commitment())
+	}
+
+	return commitments, nil
+}
+
+// GetCommitmentUtilization gets the utilization and coverage of the commitments of the kind over time, one
+// value per period of the interval of the options, in period order. Filters of the options restrict the commitments.
+// The options must at least set a time range.
+func (s *Client) GetCommitmentUtilization(ctx context.Context, kind CommitmentKind, requestOptions *OLAPReportRequestOptions) ([]CommitmentUtilization, error) {
+	report := OLAPReportReservationUtilization
+	switch kind {
+	case CommitmentReservedInstance:
+	case CommitmentSavingsPlan:
+		report = OLAPReportSavingsPlanUtilization
+	default:
+		return nil, fmt.Errorf("invalid commitment kind `%s`", kind)
+	}
+
+	var options OLAPReportRequestOptions
+	if requestOptions != nil {
+		options = *requestOptions
+	}
+	options.Measures = []string{MeasureUtilization, MeasureCoverage}
+	options.Dimensions = []string{DimensionTime}
+
+	olapReport, err := s.GetOLAPReportWithContext(ctx, report, &options)
+	if err != nil {
+		return nil, err
+	}
+
+	utilizationIndex := olapReport.MeasureIndex(MeasureUtilization)
+	coverageIndex := olapReport.MeasureIndex(MeasureCoverage)
+	if utilizationIndex < 0 || coverageIndex < 0 {
+		return nil, fmt.Errorf("report `%s` is missing the `%s` or `%s` measure", report, MeasureUtilization, MeasureCoverage)
+	}
+
+	cells, err := olapReport.Cells()
+	if err != nil {
+		return nil, err
+	}
+
+	var utilization []CommitmentUtilization
+	for _, cell := range cells {
+		if isAggregate(cell.Members) {
+			continue
+		}
+		if len(cell.Values) <= utilizationIndex || len(cell.Values) <= coverageIndex {
+			return nil, fmt.Errorf("report `%s` has %d values for period `%s`, expected %d", report, len(cell.Values), cell.Members[0].Name, len(olapReport.Measures))
+		}
+
+		period := CommitmentUtilization{
+			Period:      cell.Members[0].Name,
+			Utilization: cell.Values[utilizationIndex],
+			Coverage:    cell.Values[coverageIndex],
+		}
+		period.PeriodStart, period.PeriodEnd = periodBounds(options.Interval, period.Period)
+		utilization = append(utilization, period)
+	}
+
+	return utilization, nil
+}
@@ -0,0 +1,100 @@
+package cloudhealth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReservedInstanceCommitment(t *testing.T) {
+	reservedInstance := AwsReservedInstance{
+		ReservationID: "r-1",
+		InstanceType:  "m5.large",
+		InstanceCount: 2,
+		Start:         NewTimestamp(time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)),
+		End:           NewTimestamp(time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)),
+		Duration:      365 * 24 * 3600,
+		FixedPrice:    876,
+		UsagePrice:    0.05,
+		Account:       &AwsAccountAsset{OwnerID: "123456789012", Name: "Production"},
+	}
+
+	commitment := reservedInstance.Commitment()
+	assert.Equal(t, CommitmentReservedInstance, commitment.Kind)
+	assert.Equal(t, "123456789012", commitment.AccountID)
+	// Both instances are paid upfront: 2 × (0.05 + 876 / 8760)
+	assert.InDelta(t, 0.3, commitment.HourlyCommitment, 0.0001)
+	assert.Equal(t, 12, commitment.TermMonths())
+	assert.Equal(t, 30, commitment.DaysUntilExpiry(time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)))
+}
+
+func TestSavingsPlanLenientDates(t *testing.T) {
+	var savingsPlans []AwsSavingsPlan
+	err := json.Unmarshal([]byte(`[{"savings_plan_id": "sp-1", "start": "", "end": "2024-03-01 00:00:00 UTC", "commitment": 2.5}]`), &savingsPlans)
+	assert.NoError(t, err)
+
+	commitment := savingsPlans[0].Commitment()
+	assert.True(t, commitment.Start.IsZero())
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), commitment.End)
+}
+
+func TestGetCommitmentUtilizationMissingValues(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"status": "complete",
+			"dimensions": [{"time": [{"name": "2022-01"}]}],
+			"measures": [{"name": "utilization"}, {"name": "coverage"}],
+			"data": [[98.5]]
+		}`))
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+
+	_, err = c.GetCommitmentUtilization(context.Background(), CommitmentReservedInstance, &OLAPReportRequestOptions{Interval: IntervalMonthly, TimeRange: TimeIndices(-1)})
+	assert.EqualError(t, err, "report `ri_utilization/history` has 1 values for period `2022-01`, expected 2")
+}
+
+func TestGetCommitmentUtilization(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/olap_reports/savings_plan_utilization/history", r.URL.EscapedPath())
+		assert.Equal(t, []string{MeasureUtilization, MeasureCoverage}, r.URL.Query()["measures[]"])
+
+		w.Write([]byte(`{
+			"report": "savings_plan_utilization/history",
+			"status": "complete",
+			"dimensions": [{"time": [{"name": "total"}, {"name": "2022-01"}, {"name": "2022-02"}]}],
+			"measures": [{"name": "utilization"}, {"name": "coverage"}],
+			"data": [[95.0, 61.0], [98.5, 60.0], [91.5, 62.0]]
+		}`))
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+
+	utilization, err := c.GetCommitmentUtilization(context.Background(), CommitmentSavingsPlan, &OLAPReportRequestOptions{
+		Interval:  IntervalMonthly,
+		TimeRange: TimeIndices(-2, -1),
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, utilization, 2) {
+		assert.Equal(t, "2022-01", utilization[0].Period)
+		assert.Equal(t, time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), utilization[0].PeriodStart)
+		assert.Equal(t, 98.5, utilization[0].Utilization)
+		assert.Equal(t, 62.0, utilization[1].Coverage)
+	}
+
+	_, err = c.GetCommitmentUtilization(context.Background(), CommitmentSavingsPlan, nil)
+	assert.EqualError(t, err, "the `interval` property is required and cannot be blank")
+
+	_, err = c.GetCommitmentUtilization(context.Background(), "capacity_reservation", &OLAPReportRequestOptions{})
+	assert.EqualError(t, err, "invalid commitment kind `capacity_reservation`")
+}
@@ -2,6 +2,7 @@ package cloudhealth
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -31,11 +32,16 @@ var ErrTooManyRequest = errors.New("exceeding post rate limit")
 
 // getResponsePage returns a response page from a CloudHealth's endpoint.
 func getResponsePage(s *Client, relativeURL string) ([]byte, error) {
+	return getResponsePageWithContext(context.Background(), s, relativeURL)
+}
+
+// getResponsePageWithContext returns a response page from a CloudHealth's endpoint, bound to the given context.
+func getResponsePageWithContext(ctx context.Context, s *Client, relativeURL string) ([]byte, error) {
 	// Set up the URL
 	finalUrl := s.EndpointURL + relativeURL
 
 	// Make the physical API call
-	req, err := http.NewRequest("GET", finalUrl, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", finalUrl, nil)
 	if err != nil {
 		return []byte{}, err
 	}
@@ -49,6 +55,11 @@ func getResponsePage(s *Client, relativeURL string) ([]byte, error) {
 
 // createResource creates a resource and retrieves details from CloudHealth.
 func createResource(s *Client, relativeURL string, resource interface{}) ([]byte, error) {
+	return createResourceWithContext(context.Background(), s, relativeURL, resource)
+}
+
+// createResourceWithContext creates a resource and retrieves details from CloudHealth, bound to the given context.
+func createResourceWithContext(ctx context.Context, s *Client, relativeURL string, resource interface{}) ([]byte, error) {
 	// Create the request body
 	body, _ := json.Marshal(resource)
 
@@ -56,7 +67,7 @@ func createResource(s *Client, relativeURL string, resource interface{}) ([]byte
 	finalUrl := s.EndpointURL + relativeURL
 
 	// Make the physical API call
-	req, err := http.NewRequest("POST", finalUrl, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", finalUrl, bytes.NewBuffer(body))
 	if err != nil {
 		return []byte{}, err
 	}
@@ -70,6 +81,11 @@ func createResource(s *Client, relativeURL string, resource interface{}) ([]byte
 
 // updateResource updates a resource and retrieves details from CloudHealth.
 func updateResource(s *Client, relativeURL string, resource interface{}) ([]byte, error) {
+	return updateResourceWithContext(context.Background(), s, relativeURL, resource)
+}
+
+// updateResourceWithContext updates a resource and retrieves details from CloudHealth, bound to the given context.
+func updateResourceWithContext(ctx context.Context, s *Client, relativeURL string, resource interface{}) ([]byte, error) {
 	// Create the request body
 	body, _ := json.Marshal(resource)
 
@@ -77,7 +93,7 @@ func updateResource(s *Client, relativeURL string, resource interface{}) ([]byte
 	finalUrl := s.EndpointURL + relativeURL
 
 	// Make the physical API call
-	req, err := http.NewRequest("PUT", finalUrl, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, "PUT", finalUrl, bytes.NewBuffer(body))
 	if err != nil {
 		return []byte{}, err
 	}
@@ -91,11 +107,16 @@ func updateResource(s *Client, relativeURL string, resource interface{}) ([]byte
 
 // deleteResource deletes a resource and retrieves details from CloudHealth.
 func deleteResource(s *Client, relativeURL string) ([]byte, error) {
+	return deleteResourceWithContext(context.Background(), s, relativeURL)
+}
+
+// deleteResourceWithContext deletes a resource and retrieves details from CloudHealth, bound to the given context.
+func deleteResourceWithContext(ctx context.Context, s *Client, relativeURL string) ([]byte, error) {
 	// Set up the URL
 	finalUrl := s.EndpointURL + relativeURL
 
 	// Make the physical API call
-	req, err := http.NewRequest("DELETE", finalUrl, nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", finalUrl, nil)
 	if err != nil {
 		return []byte{}, err
 	}
@@ -107,6 +128,56 @@ func deleteResource(s *Client, relativeURL string) ([]byte, error) {
 	return sendRequest(req)
 }
 
+// graphQLRequest is the body of a request sent to CloudHealth's GraphQL endpoint.
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+// graphQLResponse is the envelope of a response returned by CloudHealth's GraphQL endpoint.
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// sendGraphQLRequest sends a query or mutation to CloudHealth's GraphQL endpoint and unmarshals its data into result.
+func sendGraphQLRequest(ctx context.Context, s *Client, query string, variables map[string]interface{}, result interface{}) error {
+	// Create the request body
+	body, _ := json.Marshal(graphQLRequest{Query: query, Variables: variables})
+
+	// Make the physical API call
+	req, err := http.NewRequestWithContext(ctx, "POST", s.GraphQLEndpointURL, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+
+	// Add headers as needed
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Authorization", s.APIKey)
+
+	responseBody, err := sendRequest(req)
+	if err != nil {
+		return err
+	}
+
+	// GraphQL reports failures in the body, even with a 200 status code
+	var response graphQLResponse
+	err = json.Unmarshal(responseBody, &response)
+	if err != nil {
+		return err
+	}
+	if len(response.Errors) > 0 {
+		return fmt.Errorf("error from CloudHealth GraphQL API: %s", response.Errors[0].Message)
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(response.Data, result)
+}
+
 // sendRequest sends request to CloudHealth and retrieves details about.
 func sendRequest(req *http.Request) ([]byte, error) {
 	// Create HTTP client for sending requests
@@ -0,0 +1,245 @@
+package cloudhealth
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// CostReport is implemented by reports whose data can be decoded into cells, such as
+// OLAPReport and AWSCostHistoryReport.
+type CostReport interface {
+	Cells() ([]OLAPCell, error)
+	MeasureIndex(name string) int
+}
+
+// CostChange represents the change of a measure for one combination of dimension members between two periods.
+type CostChange struct {
+	Key           string
+	Members       []OLAPDimensionMember
+	Previous      float64
+	Current       float64
+	Change        float64
+	PercentChange float64
+	New           bool
+	Disappeared   bool
+}
+
+// CostComparison represents the changes of a measure between two periods, sorted by key.
+type CostComparison struct {
+	Changes []CostChange
+}
+
+// CompareCostReports compares a measure between two reports covering different periods,
+// such as last month's and this month's cost history. Aggregated cells, such as totals, are skipped.
+func CompareCostReports(previous CostReport, current CostReport, measure string) (*CostComparison, error) {
+	previousCells, err := cellsForMeasure(previous, measure)
+	if err != nil {
+		return nil, err
+	}
+
+	currentCells, err := cellsForMeasure(current, measure)
+	if err != nil {
+		return nil, err
+	}
+
+	return compareCells(withoutAggregates(previousCells), withoutAggregates(currentCells)), nil
+}
+
+// CompareIntervals compares a measure between the last two periods of the time dimension of a single report.
+// Aggregated cells, such as totals, are skipped.
+func CompareIntervals(report *OLAPReport, measure string) (*CostComparison, error) {
+	timeDimension := -1
+	for i, dimension := range report.Dimensions {
+		if dimension.Name == DimensionTime {
+			timeDimension = i
+			break
+		}
+	}
+	if timeDimension < 0 {
+		return nil, fmt.Errorf("the report doesn't contain the `%s` dimension", DimensionTime)
+	}
+
+	// Find the last two periods, ignoring the aggregated total
+	var periods []string
+	for _, member := range report.Dimensions[timeDimension].Members {
+		if member.Name != TotalMember {
+			periods = append(periods, member.Name)
+		}
+	}
+	if len(periods) < 2 {
+		return nil, fmt.Errorf("the report contains %d periods, at least 2 are required", len(periods))
+	}
+	previousPeriod, currentPeriod := periods[len(periods)-2], periods[len(periods)-1]
+
+	cells, err := cellsForMeasure(report, measure)
+	if err != nil {
+		return nil, err
+	}
+
+	// Split the cells by period and drop the time member so both sides share keys
+	previousCells := make(map[string]measuredCell)
+	currentCells := make(map[string]measuredCell)
+	for _, cell := range withoutAggregates(cells) {
+		members := make([]OLAPDimensionMember, 0, len(cell.members)-1)
+		members = append(members, cell.members[:timeDimension]...)
+		members = append(members, cell.members[timeDimension+1:]...)
+		measured := measuredCell{members: members, value: cell.value}
+
+		switch cell.members[timeDimension].Name {
+		case previousPeriod:
+			previousCells[cellKey(members)] = measured
+		case currentPeriod:
+			currentCells[cellKey(members)] = measured
+		}
+	}
+
+	return compareCells(previousCells, currentCells), nil
+}
+
+// TopMovers returns the n changes with the largest absolute change, largest first.
+func (c *CostComparison) TopMovers(n int) []CostChange {
+	if n < 0 {
+		n = 0
+	}
+
+	movers := append([]CostChange(nil), c.Changes...)
+	sort.SliceStable(movers, func(i, j int) bool {
+		return math.Abs(movers[i].Change) > math.Abs(movers[j].Change)
+	})
+
+	if n < len(movers) {
+		movers = movers[:n]
+	}
+
+	return movers
+}
+
+// NewMembers returns the changes for members that had no value in the previous period.
+func (c *CostComparison) NewMembers() []CostChange {
+	var changes []CostChange
+	for _, change := range c.Changes {
+		if change.New {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+// DisappearedMembers returns the changes for members that have no value in the current period.
+func (c *CostComparison) DisappearedMembers() []CostChange {
+	var changes []CostChange
+	for _, change := range c.Changes {
+		if change.Disappeared {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+// measuredCell is a cell reduced to the value of a single measure.
+type measuredCell struct {
+	members []OLAPDimensionMember
+	value   float64
+}
+
+// cellsForMeasure decodes the cells of a report keeping only the value of the given measure.
+func cellsForMeasure(report CostReport, measure string) (map[string]measuredCell, error) {
+	index := report.MeasureIndex(measure)
+	if index < 0 {
+		return nil, fmt.Errorf("the report doesn't contain the `%s` measure", measure)
+	}
+
+	cells, err := report.Cells()
+	if err != nil {
+		return nil, err
+	}
+
+	measuredCells := make(map[string]measuredCell, len(cells))
+	for _, cell := range cells {
+		measured := measuredCell{members: cell.Members}
+		if index < len(cell.Values) {
+			measured.value = cell.Values[index]
+		}
+		measuredCells[cellKey(cell.Members)] = measured
+	}
+
+	return measuredCells, nil
+}
+
+// withoutAggregates returns the cells that don't aggregate other cells.
+func withoutAggregates(cells map[string]measuredCell) map[string]measuredCell {
+	filtered := make(map[string]measuredCell, len(cells))
+	for key, cell := range cells {
+		if !isAggregate(cell.members) {
+			filtered[key] = cell
+		}
+	}
+
+	return filtered
+}
+
+// cellKeyEscaper escapes the separator of member names in cell keys, so that names containing it don't collide.
+var cellKeyEscaper = strings.NewReplacer(`\`, `\\`, "/", `\/`)
+
+// cellKey returns a key identifying a combination of dimension members, their names joined with "/".
+func cellKey(members []OLAPDimensionMember) string {
+	names := make([]string, len(members))
+	for i, member := range members {
+		names[i] = cellKeyEscaper.Replace(member.Name)
+	}
+
+	return strings.Join(names, "/")
+}
+
+// compareCells computes the change of each key present in either period.
+func compareCells(previous map[string]measuredCell, current map[string]measuredCell) *CostComparison {
+	keys := make(map[string]bool, len(previous)+len(current))
+	for key := range previous {
+		keys[key] = true
+	}
+	for key := range current {
+		keys[key] = true
+	}
+
+	comparison := &CostComparison{}
+	for key := range keys {
+		previousCell, inPrevious := previous[key]
+		currentCell, inCurrent := current[key]
+
+		// Members without any value in either period are not interesting
+		if previousCell.value == 0 && currentCell.value == 0 {
+			continue
+		}
+
+		change := CostChange{
+			Key:      key,
+			Previous: previousCell.value,
+			Current:  currentCell.value,
+			Change:   currentCell.value - previousCell.value,
+		}
+		if inCurrent {
+			change.Members = currentCell.members
+		} else {
+			change.Members = previousCell.members
+		}
+
+		// Percentages are only meaningful when there was a previous value
+		if previousCell.value != 0 {
+			change.PercentChange = change.Change / math.Abs(previousCell.value) * 100
+		}
+		change.New = !inPrevious || previousCell.value == 0
+		change.Disappeared = !inCurrent || currentCell.value == 0
+
+		comparison.Changes = append(comparison.Changes, change)
+	}
+
+	sort.Slice(comparison.Changes, func(i, j int) bool {
+		return comparison.Changes[i].Key < comparison.Changes[j].Key
+	})
+
+	return comparison
+}
@@ -0,0 +1,113 @@
+package cloudhealth
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const previousMonthCostHistoryReport = `{
+	"report": "cost/history",
+	"dimensions": [
+		{"AWS-Service-Category": [
+			{"name": "ec2", "label": "EC2 - Compute", "parent": -1},
+			{"name": "s3", "label": "S3", "parent": -1},
+			{"name": "rds", "label": "RDS", "parent": -1},
+			{"name": "total", "label": "Total", "parent": -1}
+		]}
+	],
+	"measures": [{"name": "cost", "label": "Cost ($)"}],
+	"data": [[100.0], [50.0], [25.0], [175.0]]
+}`
+
+const currentMonthCostHistoryReport = `{
+	"report": "cost/history",
+	"dimensions": [
+		{"AWS-Service-Category": [
+			{"name": "ec2", "label": "EC2 - Compute", "parent": -1},
+			{"name": "s3", "label": "S3", "parent": -1},
+			{"name": "lambda", "label": "Lambda", "parent": -1},
+			{"name": "total", "label": "Total", "parent": -1}
+		]}
+	],
+	"measures": [{"name": "cost", "label": "Cost ($)"}],
+	"data": [[150.0], [40.0], [10.0], [200.0]]
+}`
+
+const monthlyCostHistoryReport = `{
+	"report": "cost/history",
+	"dimensions": [
+		{"time": [
+			{"name": "total", "label": "Total", "parent": -1},
+			{"name": "2022-01", "label": "Jan 2022", "parent": -1},
+			{"name": "2022-02", "label": "Feb 2022", "parent": -1}
+		]},
+		{"AWS-Account": [
+			{"name": "123", "label": "Production", "parent": -1},
+			{"name": "456", "label": "Staging", "parent": -1}
+		]}
+	],
+	"measures": [{"name": "cost", "label": "Cost ($)"}],
+	"data": [
+		[[300.0], [60.0]],
+		[[100.0], [60.0]],
+		[[200.0], [null]]
+	]
+}`
+
+func TestCompareCostReports(t *testing.T) {
+	var previous, current AWSCostHistoryReport
+	assert.NoError(t, json.Unmarshal([]byte(previousMonthCostHistoryReport), &previous))
+	assert.NoError(t, json.Unmarshal([]byte(currentMonthCostHistoryReport), &current))
+
+	comparison, err := CompareCostReports(&previous, &current, MeasureCost)
+	assert.NoError(t, err)
+	assert.Len(t, comparison.Changes, 4)
+
+	ec2 := comparison.Changes[0]
+	assert.Equal(t, "ec2", ec2.Key)
+	assert.Equal(t, 50.0, ec2.Change)
+	assert.Equal(t, 50.0, ec2.PercentChange)
+	assert.False(t, ec2.New)
+	assert.False(t, ec2.Disappeared)
+
+	s3 := comparison.Changes[3]
+	assert.Equal(t, "s3", s3.Key)
+	assert.Equal(t, -10.0, s3.Change)
+	assert.Equal(t, -20.0, s3.PercentChange)
+
+	newMembers := comparison.NewMembers()
+	assert.Len(t, newMembers, 1)
+	assert.Equal(t, "Lambda", newMembers[0].Members[0].Label)
+	assert.Equal(t, 0.0, newMembers[0].PercentChange)
+
+	disappeared := comparison.DisappearedMembers()
+	assert.Len(t, disappeared, 1)
+	assert.Equal(t, "rds", disappeared[0].Key)
+	assert.Equal(t, -25.0, disappeared[0].Change)
+
+	movers := comparison.TopMovers(2)
+	assert.Equal(t, []string{"ec2", "rds"}, []string{movers[0].Key, movers[1].Key})
+	assert.Empty(t, comparison.TopMovers(-1))
+
+	_, err = CompareCostReports(&previous, &current, "usage")
+	assert.EqualError(t, err, "the report doesn't contain the `usage` measure")
+}
+
+func TestCompareIntervals(t *testing.T) {
+	var report OLAPReport
+	assert.NoError(t, json.Unmarshal([]byte(monthlyCostHistoryReport), &report))
+
+	comparison, err := CompareIntervals(&report, MeasureCost)
+	assert.NoError(t, err)
+	assert.Len(t, comparison.Changes, 2)
+
+	assert.Equal(t, "123", comparison.Changes[0].Key)
+	assert.Equal(t, 100.0, comparison.Changes[0].Change)
+	assert.Equal(t, 100.0, comparison.Changes[0].PercentChange)
+
+	assert.Equal(t, "456", comparison.Changes[1].Key)
+	assert.True(t, comparison.Changes[1].Disappeared)
+	assert.Equal(t, -100.0, comparison.Changes[1].PercentChange)
+}
@@ -0,0 +1,319 @@
+package cloudhealth
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CloudProvider identifies the cloud a cost comes from.
+type CloudProvider string
+
+// Cloud providers supported by CloudHealth cost reports.
+const (
+	ProviderAWS   CloudProvider = "AWS"
+	ProviderAzure CloudProvider = "Azure"
+	ProviderGCP   CloudProvider = "GCP"
+)
+
+// DefaultCurrency is the currency of CostRecords when none is specified.
+const DefaultCurrency = "USD"
+
+// CostRecord represents a cost from any cloud provider in a single shape.
+// AccountID holds the AWS account, the Azure subscription or the GCP project.
+type CostRecord struct {
+	Provider    CloudProvider
+	AccountID   string
+	AccountName string
+	Service     string
+	Region      string
+	Tags        map[string]string
+	Attributes  map[string]string
+	Period      string
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	Amount      float64
+	Currency    string
+}
+
+// costRecordField identifies which field of a CostRecord a report dimension is mapped to.
+type costRecordField int
+
+const (
+	costRecordAccount costRecordField = iota + 1
+	costRecordService
+	costRecordRegion
+)
+
+// costRecordDimensions maps the dimensions of each provider's reports to CostRecord fields.
+var costRecordDimensions = map[CloudProvider]map[string]costRecordField{
+	ProviderAWS: {
+		DimensionAWSAccount:         costRecordAccount,
+		DimensionAWSServiceCategory: costRecordService,
+		DimensionAWSRegion:          costRecordRegion,
+	},
+	ProviderAzure: {
+		DimensionAzureSubscription: costRecordAccount,
+		DimensionAzureService:      costRecordService,
+		DimensionAzureRegion:       costRecordRegion,
+	},
+	ProviderGCP: {
+		DimensionGCPProject: costRecordAccount,
+		DimensionGCPService: costRecordService,
+		DimensionGCPRegion:  costRecordRegion,
+	},
+}
+
+// NormalizeOLAPReport converts a measure of a report from the given provider into CostRecords.
+// Aggregated cells, such as totals, and cells without cost are skipped. Dimensions without a
+// CostRecord field, such as Azure resource groups or Perspectives, are kept in Attributes.
+// AWS Service Categories are hierarchical: each category contributes its direct value, excluding
+// its children, so that the records of parent and child categories never double count.
+func NormalizeOLAPReport(provider CloudProvider, report *OLAPReport, measure string, currency string) ([]CostRecord, error) {
+	dimensions, ok := costRecordDimensions[provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown cloud provider `%s`", provider)
+	}
+
+	cells, err := cellsForMeasure(report, measure)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range report.Dimensions {
+		if report.Dimensions[i].Name == DimensionAWSServiceCategory {
+			err = useDirectValues(report, cells, i)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+
+	var records []CostRecord
+	for _, cell := range sortedCells(cells) {
+		if cell.value == 0 || isAggregate(cell.members) {
+			continue
+		}
+
+		record := CostRecord{
+			Provider:   provider,
+			Tags:       map[string]string{},
+			Attributes: map[string]string{},
+			Amount:     cell.value,
+			Currency:   currency,
+		}
+
+		for i, member := range cell.members {
+			name := report.Dimensions[i].Name
+
+			switch {
+			case name == DimensionTime:
+				record.Period = member.Name
+				record.PeriodStart, record.PeriodEnd = periodBounds(Interval(report.Interval), member.Name)
+			case strings.HasPrefix(name, TagDimensionPrefix):
+				if member.Name != UntaggedMember {
+					record.Tags[strings.TrimPrefix(name, TagDimensionPrefix)] = member.Name
+				}
+			case dimensions[name] == costRecordAccount:
+				record.AccountID = member.Name
+				record.AccountName = member.Label
+			case dimensions[name] == costRecordService:
+				record.Service = memberLabel(member)
+			case dimensions[name] == costRecordRegion:
+				record.Region = member.Name
+			default:
+				record.Attributes[name] = memberLabel(member)
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// NormalizeAWSCostHistoryReport converts a measure of an AWS Cost History Report covering the period from
+// periodStart to periodEnd, inclusive, into CostRecords. The report has no time dimension, so the period,
+// such as the bounds of its TimeRange, is given; Period is the name of the period containing periodStart.
+// Service Categories are hierarchical: each member contributes its direct value, excluding its children,
+// so that the records of parent and child categories never double count.
+func NormalizeAWSCostHistoryReport(report *AWSCostHistoryReport, measure string, currency string, periodStart time.Time, periodEnd time.Time) ([]CostRecord, error) {
+	tree, err := report.ServiceCategoryTree(measure)
+	if err != nil {
+		return nil, err
+	}
+
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+
+	var nodes []*DimensionNode
+	tree.Walk(func(node *DimensionNode, depth int) {
+		nodes = append(nodes, node)
+	})
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].Member.Name < nodes[j].Member.Name
+	})
+
+	var records []CostRecord
+	for _, node := range nodes {
+		if node.DirectValue == 0 {
+			continue
+		}
+
+		records = append(records, CostRecord{
+			Provider:    ProviderAWS,
+			Service:     memberLabel(node.Member),
+			Tags:        map[string]string{},
+			Attributes:  map[string]string{},
+			Period:      periodName(Interval(report.Interval), periodStart),
+			PeriodStart: periodStart,
+			PeriodEnd:   periodEnd,
+			Amount:      node.DirectValue,
+			Currency:    currency,
+		})
+	}
+
+	return records, nil
+}
+
+// CostRecords converts a measure of the report into CostRecords.
+func (r *AzureCostHistoryReport) CostRecords(measure string, currency string) ([]CostRecord, error) {
+	return NormalizeOLAPReport(ProviderAzure, &r.OLAPReport, measure, currency)
+}
+
+// CostRecords converts a measure of the report into CostRecords.
+func (r *GCPCostHistoryReport) CostRecords(measure string, currency string) ([]CostRecord, error) {
+	return NormalizeOLAPReport(ProviderGCP, &r.OLAPReport, measure, currency)
+}
+
+// useDirectValues replaces the value of each cell with the direct value of its member of the hierarchical
+// dimension at dimensionIndex, that is without the values of the member's children for the same members
+// of the other dimensions.
+func useDirectValues(report *OLAPReport, cells map[string]measuredCell, dimensionIndex int) error {
+	tree, err := BuildDimensionTree(report.Dimensions[dimensionIndex])
+	if err != nil {
+		return err
+	}
+
+	// Index members by name, names are unique within a dimension
+	indexes := make(map[string]int, len(tree.Nodes))
+	for i, node := range tree.Nodes {
+		indexes[node.Member.Name] = i
+	}
+
+	// Group the cells sharing their members of the other dimensions
+	groups := make(map[string][]string)
+	for key, cell := range cells {
+		others := make([]OLAPDimensionMember, len(cell.members))
+		copy(others, cell.members)
+		others[dimensionIndex] = OLAPDimensionMember{}
+		groupKey := cellKey(others)
+		groups[groupKey] = append(groups[groupKey], key)
+	}
+
+	for _, keys := range groups {
+		values := make([]float64, len(tree.Nodes))
+		for _, key := range keys {
+			values[indexes[cells[key].members[dimensionIndex].Name]] = cells[key].value
+		}
+		tree.Rollup(values)
+
+		for _, key := range keys {
+			cell := cells[key]
+			cell.value = tree.Nodes[indexes[cell.members[dimensionIndex].Name]].DirectValue
+			cells[key] = cell
+		}
+	}
+
+	return nil
+}
+
+// sortedCells returns the cells ordered by key so that conversions are deterministic.
+func sortedCells(cells map[string]measuredCell) []measuredCell {
+	keys := make([]string, 0, len(cells))
+	for key := range cells {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	sorted := make([]measuredCell, len(keys))
+	for i, key := range keys {
+		sorted[i] = cells[key]
+	}
+
+	return sorted
+}
+
+// isAggregate reports whether any member of a cell aggregates other members.
+func isAggregate(members []OLAPDimensionMember) bool {
+	for _, member := range members {
+		if member.Name == TotalMember {
+			return true
+		}
+	}
+
+	return false
+}
+
+// memberLabel returns the label of a dimension member, or its name when it has no label.
+func memberLabel(member OLAPDimensionMember) string {
+	if member.Label != "" {
+		return member.Label
+	}
+
+	return member.Name
+}
+
+// periodBounds parses the name of a time dimension member into the first and last instants of the period.
+// Zero times are returned when the name can't be parsed.
+func periodBounds(interval Interval, name string) (time.Time, time.Time) {
+	switch interval {
+	case IntervalMonthly:
+		start, err := time.Parse("2006-01", name)
+		if err == nil {
+			return start, start.AddDate(0, 1, 0).Add(-time.Nanosecond)
+		}
+	case IntervalWeekly:
+		start, err := time.Parse("2006-01-02", name)
+		if err == nil {
+			return start, start.AddDate(0, 0, 7).Add(-time.Nanosecond)
+		}
+	case IntervalDaily:
+		start, err := time.Parse("2006-01-02", name)
+		if err == nil {
+			return start, start.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+	case IntervalHourly:
+		start, err := time.Parse("2006-01-02T15", name)
+		if err != nil {
+			start, err = time.Parse(time.RFC3339, name)
+		}
+		if err == nil {
+			return start, start.Add(time.Hour - time.Nanosecond)
+		}
+	}
+
+	return time.Time{}, time.Time{}
+}
+
+// periodName returns the name of the time dimension member of the period containing t, the month by default.
+func periodName(interval Interval, t time.Time) string {
+	t = t.UTC()
+
+	switch interval {
+	case IntervalWeekly:
+		return t.AddDate(0, 0, -int(t.Weekday())).Format("2006-01-02")
+	case IntervalDaily:
+		return t.Format("2006-01-02")
+	case IntervalHourly:
+		return t.Format("2006-01-02T15")
+	default:
+		return t.Format("2006-01")
+	}
+}
@@ -0,0 +1,121 @@
+package cloudhealth
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const gcpCostHistoryReport = `{
+	"interval": "monthly",
+	"dimensions": [
+		{"time": [{"name": "total"}, {"name": "2022-01"}]},
+		{"GCP-Project": [{"name": "payments-prod", "label": "Payments"}]},
+		{"AWS-Tag-env": [{"name": "prod"}, {"name": "untagged"}]}
+	],
+	"measures": [{"name": "cost"}],
+	"data": [
+		[[[30.0], [5.0]]],
+		[[[30.0], [5.0]]]
+	]
+}`
+
+func TestNormalizeOLAPReport(t *testing.T) {
+	var report GCPCostHistoryReport
+	assert.NoError(t, json.Unmarshal([]byte(gcpCostHistoryReport), &report))
+
+	records, err := report.CostRecords(MeasureCost, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []CostRecord{
+		{
+			Provider:    ProviderGCP,
+			AccountID:   "payments-prod",
+			AccountName: "Payments",
+			Tags:        map[string]string{"env": "prod"},
+			Attributes:  map[string]string{},
+			Period:      "2022-01",
+			PeriodStart: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+			PeriodEnd:   time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond),
+			Amount:      30,
+			Currency:    DefaultCurrency,
+		},
+		{
+			Provider:    ProviderGCP,
+			AccountID:   "payments-prod",
+			AccountName: "Payments",
+			Tags:        map[string]string{},
+			Attributes:  map[string]string{},
+			Period:      "2022-01",
+			PeriodStart: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+			PeriodEnd:   time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond),
+			Amount:      5,
+			Currency:    DefaultCurrency,
+		},
+	}, records)
+
+	_, err = NormalizeOLAPReport("Oracle", &report.OLAPReport, MeasureCost, "")
+	assert.EqualError(t, err, "unknown cloud provider `Oracle`")
+}
+
+func TestNormalizeAWSCostHistoryReport(t *testing.T) {
+	var report AWSCostHistoryReport
+	assert.NoError(t, json.Unmarshal([]byte(serviceCategoryCostHistoryReport), &report))
+
+	start := time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0).Add(-time.Nanosecond)
+	records, err := NormalizeAWSCostHistoryReport(&report, MeasureCost, "", start, end)
+	assert.NoError(t, err)
+
+	// The Compute parent category only keeps the cost not attributed to its children
+	services := make([]string, len(records))
+	total := 0.0
+	for i, record := range records {
+		services[i] = record.Service
+		total += record.Amount
+		assert.Equal(t, "2022-02", record.Period)
+		assert.Equal(t, start, record.PeriodStart)
+		assert.Equal(t, end, record.PeriodEnd)
+	}
+	assert.Equal(t, []string{"Compute", "EC2 - Compute", "Lambda", "S3"}, services)
+	assert.Equal(t, 5.0, records[0].Amount)
+	assert.Equal(t, 170.0, total)
+}
+
+func TestNormalizeOLAPReportServiceCategories(t *testing.T) {
+	var report OLAPReport
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"interval": "monthly",
+		"dimensions": [
+			{"time": [{"name": "2022-01"}, {"name": "2022-02"}]},
+			{"AWS-Service-Category": [
+				{"name": "total", "parent": -1},
+				{"name": "compute", "label": "Compute", "parent": -1, "extended": true},
+				{"name": "ec2", "label": "EC2 - Compute", "parent": 1, "direct": true},
+				{"name": "lambda", "label": "Lambda", "parent": 1, "direct": true},
+				{"name": "s3", "label": "S3", "parent": -1, "direct": true}
+			]}
+		],
+		"measures": [{"name": "cost"}],
+		"data": [
+			[[170.0], [120.0], [100.0], [15.0], [50.0]],
+			[[90.0], [80.0], [80.0], [0.0], [10.0]]
+		]
+	}`), &report))
+
+	records, err := NormalizeOLAPReport(ProviderAWS, &report, MeasureCost, "")
+	assert.NoError(t, err)
+
+	// The Compute parent category only keeps the cost not attributed to its children
+	totals := map[string]float64{}
+	amounts := map[string]float64{}
+	for _, record := range records {
+		totals[record.Period] += record.Amount
+		amounts[record.Period+"/"+record.Service] = record.Amount
+	}
+	assert.Equal(t, map[string]float64{"2022-01": 170, "2022-02": 90}, totals)
+	assert.Equal(t, 5.0, amounts["2022-01/Compute"])
+	assert.Equal(t, 100.0, amounts["2022-01/EC2 - Compute"])
+	assert.Len(t, records, 6)
+}
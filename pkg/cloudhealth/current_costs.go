@@ -0,0 +1,77 @@
+package cloudhealth
+
+import (
+	"fmt"
+)
+
+// CurrentCostRequestOptions represents the possible options to specify when making a request against the current cost report.
+type CurrentCostRequestOptions struct {
+	Dimensions  []string
+	Filters     []OLAPFilter
+	ClientAPIID string
+}
+
+// CurrentCost represents the month-to-date cost and projected month-end cost for one combination of dimension members.
+type CurrentCost struct {
+	Members     []OLAPDimensionMember
+	MonthToDate float64
+	Forecast    float64
+}
+
+// GetCurrentCostReport gets the Current Cost Report with both the month-to-date and projected cost measures.
+// Results are grouped by AWS Service Category when no dimensions are specified.
+func (s *Client) GetCurrentCostReport(requestOptions *CurrentCostRequestOptions) (*OLAPReport, error) {
+	dimensions := requestOptions.Dimensions
+	if len(dimensions) == 0 {
+		dimensions = []string{DimensionAWSServiceCategory}
+	}
+
+	return s.GetOLAPReport(OLAPReportCostCurrent, &OLAPReportRequestOptions{
+		Measures:    []string{MeasureCost, MeasureProjectedCost},
+		Dimensions:  dimensions,
+		Filters:     requestOptions.Filters,
+		ClientAPIID: requestOptions.ClientAPIID,
+	})
+}
+
+// GetCurrentCosts gets the month-to-date and projected cost for each combination of dimension members.
+func (s *Client) GetCurrentCosts(requestOptions *CurrentCostRequestOptions) ([]CurrentCost, error) {
+	report, err := s.GetCurrentCostReport(requestOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return report.CurrentCosts()
+}
+
+// CurrentCosts decodes the month-to-date and projected cost of each cell of a Current Cost Report.
+func (r *OLAPReport) CurrentCosts() ([]CurrentCost, error) {
+	costIndex := r.MeasureIndex(MeasureCost)
+	if costIndex < 0 {
+		return nil, fmt.Errorf("the report doesn't contain the `%s` measure", MeasureCost)
+	}
+
+	projectedIndex := r.MeasureIndex(MeasureProjectedCost)
+	if projectedIndex < 0 {
+		return nil, fmt.Errorf("the report doesn't contain the `%s` measure", MeasureProjectedCost)
+	}
+
+	cells, err := r.Cells()
+	if err != nil {
+		return nil, err
+	}
+
+	currentCosts := make([]CurrentCost, 0, len(cells))
+	for _, cell := range cells {
+		currentCost := CurrentCost{Members: cell.Members}
+		if costIndex < len(cell.Values) {
+			currentCost.MonthToDate = cell.Values[costIndex]
+		}
+		if projectedIndex < len(cell.Values) {
+			currentCost.Forecast = cell.Values[projectedIndex]
+		}
+		currentCosts = append(currentCosts, currentCost)
+	}
+
+	return currentCosts, nil
+}
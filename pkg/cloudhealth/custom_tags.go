@@ -0,0 +1,158 @@
+package cloudhealth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrCustomTagsFailed is returned when custom tags could not be applied to at least one asset.
+var ErrCustomTagsFailed = errors.New("custom tags update failed")
+
+// CustomTagsMaxAssetsPerRequest is the number of assets the custom tags endpoint accepts in one request.
+const CustomTagsMaxAssetsPerRequest = 500
+
+// CustomTag represents a CloudHealth custom tag. A nil Value removes the tag from the assets.
+type CustomTag struct {
+	Key   string  `json:"key"`
+	Value *string `json:"value"`
+}
+
+// CustomTagGroup represents custom tags to apply to assets of one asset type, such as AwsInstance.
+type CustomTagGroup struct {
+	AssetType string      `json:"asset_type"`
+	IDs       []int64     `json:"ids"`
+	Tags      []CustomTag `json:"tags"`
+}
+
+// CustomTagFailure represents an asset the custom tags could not be applied to.
+type CustomTagFailure struct {
+	AssetType string
+	AssetID   int64
+	Error     string
+}
+
+// CustomTagResult represents the outcome of a custom tags update: the updated assets by asset type, and the failures.
+type CustomTagResult struct {
+	Updated  map[string][]int64
+	Failures []CustomTagFailure
+}
+
+// customTagsRequest is the body of a request to the custom tags endpoint.
+type customTagsRequest struct {
+	TagGroups []CustomTagGroup `json:"tag_groups"`
+}
+
+// customTagsResponse is the body of a response of the custom tags endpoint.
+type customTagsResponse struct {
+	Errors []struct {
+		AssetType string `json:"asset_type"`
+		AssetID   int64  `json:"asset_id"`
+		Message   string `json:"message"`
+	} `json:"errors"`
+}
+
+// SetCustomTag returns a CustomTag setting key to value.
+func SetCustomTag(key string, value string) CustomTag {
+	return CustomTag{Key: key, Value: &value}
+}
+
+// RemoveCustomTag returns a CustomTag removing key.
+func RemoveCustomTag(key string) CustomTag {
+	return CustomTag{Key: key}
+}
+
+// chunkCustomTagGroups splits the groups into requests of at most maxAssets assets.
+func chunkCustomTagGroups(groups []CustomTagGroup, maxAssets int) [][]CustomTagGroup {
+	var chunks [][]CustomTagGroup
+	var chunk []CustomTagGroup
+	count := 0
+
+	for _, group := range groups {
+		ids := group.IDs
+		for len(ids) > 0 {
+			if count == maxAssets {
+				chunks = append(chunks, chunk)
+				chunk, count = nil, 0
+			}
+
+			n := maxAssets - count
+			if n > len(ids) {
+				n = len(ids)
+			}
+			chunk = append(chunk, CustomTagGroup{AssetType: group.AssetType, IDs: ids[:n], Tags: group.Tags})
+			count += n
+			ids = ids[n:]
+		}
+	}
+	if count > 0 {
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks
+}
+
+// UpdateCustomTags adds, updates or removes custom tags on the assets of the groups, splitting them into
+// requests within the API limit. Every request is attempted; assets of a request rejected as a whole are
+// all reported as failures, and ErrCustomTagsFailed is returned along with the result when there is any.
+func (s *Client) UpdateCustomTags(ctx context.Context, groups []CustomTagGroup) (*CustomTagResult, error) {
+	for _, group := range groups {
+		if group.AssetType == "" {
+			return nil, errors.New("custom tag group has no asset type")
+		}
+		if len(group.Tags) == 0 {
+			return nil, fmt.Errorf("custom tag group of `%s` has no tags", group.AssetType)
+		}
+		for _, tag := range group.Tags {
+			if tag.Key == "" {
+				return nil, fmt.Errorf("custom tag group of `%s` has a tag without key", group.AssetType)
+			}
+		}
+	}
+
+	result := &CustomTagResult{Updated: make(map[string][]int64)}
+
+	for _, chunk := range chunkCustomTagGroups(groups, CustomTagsMaxAssetsPerRequest) {
+		// Make the API call
+		responseBody, err := createResourceWithContext(ctx, s, "v1/custom_tags", customTagsRequest{TagGroups: chunk})
+
+		// Unmarshal the response data into the customTagsResponse struct
+		var response customTagsResponse
+		if err == nil && len(responseBody) > 0 {
+			err = json.Unmarshal(responseBody, &response)
+		}
+
+		failed := make(map[string]map[int64]string)
+		for _, group := range chunk {
+			failed[group.AssetType] = make(map[int64]string)
+			if err != nil {
+				for _, id := range group.IDs {
+					failed[group.AssetType][id] = err.Error()
+				}
+			}
+		}
+		for _, e := range response.Errors {
+			if _, ok := failed[e.AssetType]; !ok {
+				failed[e.AssetType] = make(map[int64]string)
+			}
+			failed[e.AssetType][e.AssetID] = e.Message
+		}
+
+		for _, group := range chunk {
+			for _, id := range group.IDs {
+				if message, ok := failed[group.AssetType][id]; ok {
+					result.Failures = append(result.Failures, CustomTagFailure{AssetType: group.AssetType, AssetID: id, Error: message})
+					continue
+				}
+				result.Updated[group.AssetType] = append(result.Updated[group.AssetType], id)
+			}
+		}
+	}
+
+	if len(result.Failures) > 0 {
+		return result, fmt.Errorf("%w: %d assets", ErrCustomTagsFailed, len(result.Failures))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,65 @@
+package cloudhealth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateCustomTags(t *testing.T) {
+	var requestSizes []int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/custom_tags", r.URL.EscapedPath())
+		assert.Equal(t, "POST", r.Method)
+
+		var request customTagsRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		size := 0
+		for _, group := range request.TagGroups {
+			size += len(group.IDs)
+			assert.Nil(t, group.Tags[1].Value)
+		}
+		requestSizes = append(requestSizes, size)
+
+		// Reject one asset of the first request, and the whole second request
+		switch len(requestSizes) {
+		case 1:
+			w.Write([]byte(`{"errors": [{"asset_type": "AwsInstance", "asset_id": 3, "message": "asset not found"}]}`))
+		default:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+
+	instances := make([]int64, CustomTagsMaxAssetsPerRequest)
+	for i := range instances {
+		instances[i] = int64(i + 1)
+	}
+	tags := []CustomTag{SetCustomTag("owner", "payments"), RemoveCustomTag("temporary")}
+	groups := []CustomTagGroup{
+		{AssetType: "AwsInstance", IDs: instances, Tags: tags},
+		{AssetType: "AwsVolume", IDs: []int64{10, 11}, Tags: tags},
+	}
+
+	result, err := c.UpdateCustomTags(context.Background(), groups)
+	assert.ErrorIs(t, err, ErrCustomTagsFailed)
+	assert.Equal(t, []int{CustomTagsMaxAssetsPerRequest, 2}, requestSizes)
+
+	assert.Len(t, result.Updated["AwsInstance"], CustomTagsMaxAssetsPerRequest-1)
+	assert.Empty(t, result.Updated["AwsVolume"])
+	if assert.Len(t, result.Failures, 3) {
+		assert.Equal(t, CustomTagFailure{AssetType: "AwsInstance", AssetID: 3, Error: "asset not found"}, result.Failures[0])
+		assert.Equal(t, CustomTagFailure{AssetType: "AwsVolume", AssetID: 10, Error: ErrUnprocessableEntityError.Error()}, result.Failures[1])
+	}
+
+	_, err = c.UpdateCustomTags(context.Background(), []CustomTagGroup{{AssetType: "AwsVolume", IDs: []int64{1}}})
+	assert.EqualError(t, err, "custom tag group of `AwsVolume` has no tags")
+}
@@ -0,0 +1,225 @@
+package cloudhealth
+
+import (
+	"fmt"
+)
+
+// DimensionNode represents a member of a hierarchical dimension with its place in the tree and rolled up values.
+// Value is the value reported by CloudHealth; it includes the values of descendants when the member is Extended.
+// DirectValue excludes descendants and TotalValue includes them, so summing DirectValue over all nodes or
+// TotalValue over the roots never double counts.
+type DimensionNode struct {
+	Index       int
+	Member      OLAPDimensionMember
+	Parent      *DimensionNode
+	Children    []*DimensionNode
+	Value       float64
+	DirectValue float64
+	TotalValue  float64
+}
+
+// DimensionTree represents the hierarchy of the members of a dimension, such as AWS Service Categories.
+type DimensionTree struct {
+	Dimension string
+	Roots     []*DimensionNode
+	Nodes     []*DimensionNode
+}
+
+// BuildDimensionTree reconstructs the hierarchy of a dimension from the Parent index of its members.
+// Members whose Parent is negative or refers to themselves are roots; the total member is left out of the tree.
+func BuildDimensionTree(dimension OLAPDimension) (*DimensionTree, error) {
+	tree := &DimensionTree{
+		Dimension: dimension.Name,
+		Nodes:     make([]*DimensionNode, len(dimension.Members)),
+	}
+
+	for i, member := range dimension.Members {
+		tree.Nodes[i] = &DimensionNode{Index: i, Member: member}
+	}
+
+	for i, node := range tree.Nodes {
+		if node.Member.Name == TotalMember {
+			continue
+		}
+
+		parent := int(node.Member.Parent)
+		if parent < 0 || parent == i {
+			tree.Roots = append(tree.Roots, node)
+			continue
+		}
+		if parent >= len(tree.Nodes) {
+			return nil, fmt.Errorf("member `%s` of dimension `%s` has unknown parent %d", node.Member.Name, dimension.Name, parent)
+		}
+
+		// Members placed under the total are top-level members
+		if tree.Nodes[parent].Member.Name == TotalMember {
+			tree.Roots = append(tree.Roots, node)
+			continue
+		}
+
+		node.Parent = tree.Nodes[parent]
+		node.Parent.Children = append(node.Parent.Children, node)
+	}
+
+	// Every node must be reachable from a root, otherwise the parents form a cycle
+	reachable := 0
+	tree.Walk(func(node *DimensionNode, depth int) {
+		reachable++
+	})
+	expected := 0
+	for _, node := range tree.Nodes {
+		if node.Member.Name != TotalMember {
+			expected++
+		}
+	}
+	if reachable != expected {
+		return nil, fmt.Errorf("the members of dimension `%s` have cyclic parents", dimension.Name)
+	}
+
+	return tree, nil
+}
+
+// Rollup sets the value of each member, indexed like the dimension's members, and computes the direct
+// and total values of every node.
+func (t *DimensionTree) Rollup(values []float64) {
+	for i, node := range t.Nodes {
+		node.Value = 0
+		if i < len(values) {
+			node.Value = values[i]
+		}
+	}
+
+	for _, root := range t.Roots {
+		root.rollup()
+	}
+}
+
+// rollup computes the direct and total values of the node from its children.
+func (n *DimensionNode) rollup() {
+	childrenTotal := 0.0
+	for _, child := range n.Children {
+		child.rollup()
+		childrenTotal += child.TotalValue
+	}
+
+	if n.Member.Extended {
+		n.TotalValue = n.Value
+		n.DirectValue = n.Value - childrenTotal
+	} else {
+		n.DirectValue = n.Value
+		n.TotalValue = n.Value + childrenTotal
+	}
+}
+
+// Total returns the sum of the total values of the roots.
+func (t *DimensionTree) Total() float64 {
+	total := 0.0
+	for _, root := range t.Roots {
+		total += root.TotalValue
+	}
+
+	return total
+}
+
+// Find returns the node of the member with the given name.
+func (t *DimensionTree) Find(name string) (*DimensionNode, bool) {
+	for _, node := range t.Nodes {
+		if node.Member.Name == name {
+			return node, true
+		}
+	}
+
+	return nil, false
+}
+
+// Walk calls fn for every node of the tree, parents before their children, with the depth of the node.
+func (t *DimensionTree) Walk(fn func(node *DimensionNode, depth int)) {
+	for _, root := range t.Roots {
+		root.walk(fn, 0)
+	}
+}
+
+// walk calls fn for the node and its descendants.
+func (n *DimensionNode) walk(fn func(node *DimensionNode, depth int), depth int) {
+	fn(n, depth)
+	for _, child := range n.Children {
+		child.walk(fn, depth+1)
+	}
+}
+
+// DimensionTree builds the hierarchy of a dimension of the report and rolls up a measure,
+// summed over the non-aggregate members of the other dimensions.
+func (r *OLAPReport) DimensionTree(dimension string, measure string) (*DimensionTree, error) {
+	dimensionIndex := -1
+	for i := range r.Dimensions {
+		if r.Dimensions[i].Name == dimension {
+			dimensionIndex = i
+			break
+		}
+	}
+	if dimensionIndex < 0 {
+		return nil, fmt.Errorf("the report doesn't contain the `%s` dimension", dimension)
+	}
+
+	tree, err := BuildDimensionTree(r.Dimensions[dimensionIndex])
+	if err != nil {
+		return nil, err
+	}
+
+	cells, err := cellsForMeasure(r, measure)
+	if err != nil {
+		return nil, err
+	}
+
+	// Index members by name, names are unique within a dimension
+	indexes := make(map[string]int, len(tree.Nodes))
+	for i, node := range tree.Nodes {
+		indexes[node.Member.Name] = i
+	}
+
+	values := make([]float64, len(tree.Nodes))
+	for _, cell := range cells {
+		others := make([]OLAPDimensionMember, 0, len(cell.members)-1)
+		others = append(others, cell.members[:dimensionIndex]...)
+		others = append(others, cell.members[dimensionIndex+1:]...)
+		if isAggregate(others) {
+			continue
+		}
+
+		values[indexes[cell.members[dimensionIndex].Name]] += cell.value
+	}
+	tree.Rollup(values)
+
+	return tree, nil
+}
+
+// ServiceCategoryTree builds the hierarchy of AWS Service Categories of the report and rolls up a measure.
+func (r *AWSCostHistoryReport) ServiceCategoryTree(measure string) (*DimensionTree, error) {
+	var dimension OLAPDimension
+	for _, reportDimension := range r.Dimensions {
+		if len(reportDimension.AwsServiceCategory) > 0 {
+			dimension = OLAPDimension{Name: DimensionAWSServiceCategory, Members: reportDimension.AwsServiceCategory}
+			break
+		}
+	}
+
+	tree, err := BuildDimensionTree(dimension)
+	if err != nil {
+		return nil, err
+	}
+
+	index := r.MeasureIndex(measure)
+	if index < 0 {
+		return nil, fmt.Errorf("the report doesn't contain the `%s` measure", measure)
+	}
+
+	values := make([]float64, len(tree.Nodes))
+	for i, row := range r.Data {
+		if i < len(values) && index < len(row) {
+			values[i] = row[index]
+		}
+	}
+	tree.Rollup(values)
+
+	return tree, nil
+}
@@ -0,0 +1,61 @@
+package cloudhealth
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const serviceCategoryCostHistoryReport = `{
+	"dimensions": [
+		{"AWS-Service-Category": [
+			{"name": "compute", "label": "Compute", "parent": -1, "extended": true},
+			{"name": "ec2", "label": "EC2 - Compute", "parent": 0, "direct": true},
+			{"name": "lambda", "label": "Lambda", "parent": 0, "direct": true},
+			{"name": "s3", "label": "S3", "parent": -1, "direct": true}
+		]}
+	],
+	"measures": [{"name": "cost"}],
+	"data": [[120.0], [100.0], [15.0], [50.0]]
+}`
+
+func TestServiceCategoryTree(t *testing.T) {
+	var report AWSCostHistoryReport
+	assert.NoError(t, json.Unmarshal([]byte(serviceCategoryCostHistoryReport), &report))
+
+	tree, err := report.ServiceCategoryTree(MeasureCost)
+	assert.NoError(t, err)
+	assert.Len(t, tree.Roots, 2)
+	assert.Equal(t, 170.0, tree.Total())
+
+	compute, ok := tree.Find("compute")
+	assert.True(t, ok)
+	assert.Len(t, compute.Children, 2)
+	assert.Equal(t, 120.0, compute.TotalValue)
+	assert.Equal(t, 5.0, compute.DirectValue)
+
+	ec2, _ := tree.Find("ec2")
+	assert.Equal(t, compute, ec2.Parent)
+	assert.Equal(t, 100.0, ec2.TotalValue)
+
+	direct := 0.0
+	var labels []string
+	tree.Walk(func(node *DimensionNode, depth int) {
+		direct += node.DirectValue
+		labels = append(labels, node.Member.Label)
+	})
+	assert.Equal(t, 170.0, direct)
+	assert.Equal(t, []string{"Compute", "EC2 - Compute", "Lambda", "S3"}, labels)
+}
+
+func TestBuildDimensionTreeCycle(t *testing.T) {
+	_, err := BuildDimensionTree(OLAPDimension{
+		Name: DimensionAWSServiceCategory,
+		Members: []OLAPDimensionMember{
+			{Name: "a", Parent: 1},
+			{Name: "b", Parent: 0},
+		},
+	})
+	assert.EqualError(t, err, "the members of dimension `AWS-Service-Category` have cyclic parents")
+}
@@ -0,0 +1,491 @@
+package cloudhealth
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrFlexReportFailed is returned when the execution of a FlexReport ends in a failed state.
+var ErrFlexReportFailed = errors.New("FlexReport execution failed")
+
+// ErrFlexReportPageOutOfRange is returned when requesting a result page that doesn't exist.
+var ErrFlexReportPageOutOfRange = errors.New("FlexReport result page out of range")
+
+// FlexReportDataset is a dataset that can be queried by a FlexReport.
+type FlexReportDataset string
+
+// Datasets available to FlexReports.
+const (
+	FlexReportDatasetAwsCur          FlexReportDataset = "AWS_CUR"
+	FlexReportDatasetAzureBilling    FlexReportDataset = "AZURE_BILLING"
+	FlexReportDatasetGcpBilling      FlexReportDataset = "GCP_BILLING"
+	FlexReportDatasetAwsAssetMetrics FlexReportDataset = "AWS_ASSET_METRICS"
+)
+
+// FlexReportGranularity is the granularity of the data returned by a FlexReport.
+type FlexReportGranularity string
+
+// Granularities available to FlexReports.
+const (
+	FlexReportGranularityHourly  FlexReportGranularity = "HOURLY"
+	FlexReportGranularityDaily   FlexReportGranularity = "DAILY"
+	FlexReportGranularityMonthly FlexReportGranularity = "MONTHLY"
+)
+
+// FlexReportStatus is the execution status of a FlexReport result.
+type FlexReportStatus string
+
+// Execution statuses of a FlexReport result.
+const (
+	FlexReportStatusSubmitted FlexReportStatus = "SUBMITTED"
+	FlexReportStatusRunning   FlexReportStatus = "RUNNING"
+	FlexReportStatusCompleted FlexReportStatus = "COMPLETED"
+	FlexReportStatusFailed    FlexReportStatus = "FAILED"
+)
+
+// FlexReport represents a saved FlexReport in CloudHealth.
+type FlexReport struct {
+	ID             string            `json:"id,omitempty"`
+	Name           string            `json:"name"`
+	Description    string            `json:"description,omitempty"`
+	NotifyViaEmail bool              `json:"notifyViaEmail"`
+	CreatedBy      string            `json:"createdBy,omitempty"`
+	LastUpdatedOn  string            `json:"lastUpdatedOn,omitempty"`
+	Query          FlexReportQuery   `json:"query"`
+	Result         *FlexReportResult `json:"result,omitempty"`
+}
+
+// FlexReportQuery represents the SQL-style query of a FlexReport.
+type FlexReportQuery struct {
+	SQLStatement           string                `json:"sqlStatement"`
+	DataGranularity        FlexReportGranularity `json:"dataGranularity"`
+	TimeRange              FlexReportTimeRange   `json:"timeRange"`
+	Limit                  int                   `json:"limit,omitempty"`
+	NeedBackLinkingForTags bool                  `json:"needBackLinkingForTags"`
+}
+
+// FlexReportTimeRange represents the time range of a FlexReport, either the last N periods or a From/To date range.
+type FlexReportTimeRange struct {
+	Last int    `json:"last,omitempty"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// FlexReportResult represents the latest execution of a FlexReport.
+type FlexReportResult struct {
+	Status          FlexReportStatus    `json:"status"`
+	ReportUpdatedOn string              `json:"reportUpdatedOn"`
+	ContentType     string              `json:"contentType"`
+	Contents        []FlexReportContent `json:"contents"`
+}
+
+// FlexReportContent represents one downloadable page of a FlexReport result.
+type FlexReportContent struct {
+	Key       string `json:"key"`
+	SignedURL string `json:"signedUrl"`
+}
+
+// FlexReportRow represents a single row of a FlexReport result, keyed by column name.
+type FlexReportRow map[string]string
+
+// flexReportFields lists the FlexReport fields requested from the GraphQL API.
+const flexReportFields = `
+	id
+	name
+	description
+	notifyViaEmail
+	createdBy
+	lastUpdatedOn
+	query {
+		sqlStatement
+		dataGranularity
+		limit
+		needBackLinkingForTags
+		timeRange { last from to }
+	}
+	result {
+		status
+		reportUpdatedOn
+		contentType
+		contents { key signedUrl }
+	}`
+
+// BuildFlexReportSQL builds the SQL statement of a FlexReport selecting columns from a dataset.
+func BuildFlexReportSQL(dataset FlexReportDataset, columns []string, conditions []string, groupBy []string) string {
+	statement := fmt.Sprintf("SELECT %s FROM %s", strings.Join(columns, ", "), dataset)
+
+	if len(conditions) > 0 {
+		statement = fmt.Sprintf("%s WHERE %s", statement, strings.Join(conditions, " AND "))
+	}
+
+	if len(groupBy) > 0 {
+		statement = fmt.Sprintf("%s GROUP BY %s", statement, strings.Join(groupBy, ", "))
+	}
+
+	return statement
+}
+
+// Validate checks that the FlexReport definition can be sent to CloudHealth.
+func (r FlexReport) Validate() error {
+	if r.Name == "" {
+		return errors.New("the `Name` property is required and cannot be blank")
+	}
+
+	if r.Query.SQLStatement == "" {
+		return errors.New("the `Query.SQLStatement` property is required and cannot be blank")
+	}
+
+	if r.Query.DataGranularity == "" {
+		return errors.New("the `Query.DataGranularity` property is required and cannot be blank")
+	}
+
+	timeRange := r.Query.TimeRange
+	if timeRange.Last == 0 && (timeRange.From == "" || timeRange.To == "") {
+		return errors.New("the `Query.TimeRange` property requires either `Last` or both `From` and `To`")
+	}
+	if timeRange.Last != 0 && (timeRange.From != "" || timeRange.To != "") {
+		return errors.New("the `Query.TimeRange` property cannot set both `Last` and `From`/`To`")
+	}
+
+	return nil
+}
+
+// flexReportInput converts the FlexReport into the input expected by the GraphQL mutations.
+func (r FlexReport) flexReportInput() map[string]interface{} {
+	return map[string]interface{}{
+		"name":           r.Name,
+		"description":    r.Description,
+		"notifyViaEmail": r.NotifyViaEmail,
+		"query":          r.Query,
+	}
+}
+
+// GetFlexReport gets the FlexReport with the specified CloudHealth ID.
+func (s *Client) GetFlexReport(ctx context.Context, id string) (*FlexReport, error) {
+	query := fmt.Sprintf(`query GetFlexReport($id: ID!) {
+	node(id: $id) {
+		... on FlexReport {%s
+		}
+	}
+}`, flexReportFields)
+
+	// Make the API call
+	var data struct {
+		Node *FlexReport `json:"node"`
+	}
+	err := sendGraphQLRequest(ctx, s, query, map[string]interface{}{"id": id}, &data)
+	if err != nil {
+		return nil, err
+	}
+	if data.Node == nil {
+		return nil, ErrNotFound
+	}
+
+	return data.Node, nil
+}
+
+// GetFlexReports gets all saved FlexReports.
+func (s *Client) GetFlexReports(ctx context.Context) ([]FlexReport, error) {
+	query := fmt.Sprintf(`query GetFlexReports {
+	flexReports {%s
+	}
+}`, flexReportFields)
+
+	// Make the API call
+	var data struct {
+		FlexReports []FlexReport `json:"flexReports"`
+	}
+	err := sendGraphQLRequest(ctx, s, query, nil, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.FlexReports, nil
+}
+
+// CreateFlexReport saves a new FlexReport in CloudHealth.
+func (s *Client) CreateFlexReport(ctx context.Context, report FlexReport) (*FlexReport, error) {
+	err := report.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`mutation CreateFlexReport($input: FlexReportInput!) {
+	createFlexReport(input: $input) {%s
+	}
+}`, flexReportFields)
+
+	// Make the API call
+	var data struct {
+		CreateFlexReport FlexReport `json:"createFlexReport"`
+	}
+	err = sendGraphQLRequest(ctx, s, query, map[string]interface{}{"input": report.flexReportInput()}, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.CreateFlexReport, nil
+}
+
+// UpdateFlexReport updates an existing FlexReport in CloudHealth.
+func (s *Client) UpdateFlexReport(ctx context.Context, report FlexReport) (*FlexReport, error) {
+	if report.ID == "" {
+		return nil, errors.New("the `ID` property is required and cannot be blank")
+	}
+
+	err := report.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`mutation UpdateFlexReport($id: ID!, $input: FlexReportInput!) {
+	updateFlexReport(id: $id, input: $input) {%s
+	}
+}`, flexReportFields)
+
+	// Make the API call
+	var data struct {
+		UpdateFlexReport FlexReport `json:"updateFlexReport"`
+	}
+	err = sendGraphQLRequest(ctx, s, query, map[string]interface{}{"id": report.ID, "input": report.flexReportInput()}, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.UpdateFlexReport, nil
+}
+
+// DeleteFlexReport removes the FlexReport with the specified CloudHealth ID.
+func (s *Client) DeleteFlexReport(ctx context.Context, id string) error {
+	query := `mutation DeleteFlexReport($id: ID!) {
+	delete(id: $id)
+}`
+
+	// Make the API call
+	return sendGraphQLRequest(ctx, s, query, map[string]interface{}{"id": id}, nil)
+}
+
+// TriggerFlexReport starts a new execution of the FlexReport with the specified CloudHealth ID.
+func (s *Client) TriggerFlexReport(ctx context.Context, id string) error {
+	query := `mutation TriggerFlexReport($id: ID!) {
+	triggerFlexReportExecution(id: $id) {
+		id
+	}
+}`
+
+	// Make the API call
+	return sendGraphQLRequest(ctx, s, query, map[string]interface{}{"id": id}, nil)
+}
+
+// WaitForFlexReport polls the FlexReport with the specified CloudHealth ID every pollInterval
+// until its execution completes, fails, or the context is done. A pollInterval that is not positive
+// defaults to DefaultReportPollInitialInterval.
+func (s *Client) WaitForFlexReport(ctx context.Context, id string, pollInterval time.Duration) (*FlexReportResult, error) {
+	if pollInterval <= 0 {
+		pollInterval = DefaultReportPollInitialInterval
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		report, err := s.GetFlexReport(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if report.Result != nil {
+			switch report.Result.Status {
+			case FlexReportStatusCompleted:
+				return report.Result, nil
+			case FlexReportStatusFailed:
+				return report.Result, ErrFlexReportFailed
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunFlexReport triggers the FlexReport with the specified CloudHealth ID, waits for it to complete
+// and downloads all of its result rows.
+func (s *Client) RunFlexReport(ctx context.Context, id string, pollInterval time.Duration) ([]FlexReportRow, error) {
+	err := s.TriggerFlexReport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.WaitForFlexReport(ctx, id, pollInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetFlexReportResults(ctx, result)
+}
+
+// GetFlexReportResultPage downloads and parses a single page of a FlexReport result.
+func (s *Client) GetFlexReportResultPage(ctx context.Context, result *FlexReportResult, page int) ([]FlexReportRow, error) {
+	if page < 0 || page >= len(result.Contents) {
+		return nil, ErrFlexReportPageOutOfRange
+	}
+
+	// Signed URLs carry their own credentials, so no Authorization header is added
+	req, err := http.NewRequestWithContext(ctx, "GET", result.Contents[page].SignedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, err := sendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseFlexReportCSV(responseBody)
+}
+
+// GetFlexReportResults downloads and parses all pages of a FlexReport result.
+func (s *Client) GetFlexReportResults(ctx context.Context, result *FlexReportResult) ([]FlexReportRow, error) {
+	var rows []FlexReportRow
+
+	// Loop for paging
+	for page := range result.Contents {
+		pageRows, err := s.GetFlexReportResultPage(ctx, result, page)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, pageRows...)
+	}
+
+	return rows, nil
+}
+
+// parseFlexReportCSV parses a CSV FlexReport result page into rows keyed by the header columns.
+func parseFlexReportCSV(body []byte) ([]FlexReportRow, error) {
+	reader := csv.NewReader(bytes.NewReader(body))
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []FlexReportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		row := make(FlexReportRow, len(header))
+		for i, column := range header {
+			row[column] = record[i]
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// Float64 returns the value of the column parsed as a float64. Empty values are returned as 0.
+func (r FlexReportRow) Float64(column string) (float64, error) {
+	value := r[column]
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.ParseFloat(value, 64)
+}
+
+// DecodeFlexReportRows decodes rows into out, which must be a pointer to a slice of structs.
+// Struct fields are matched to columns with the `flex` tag and may be strings, integers, floats,
+// booleans or time.Time values in RFC 3339 or YYYY-MM-DD format.
+func DecodeFlexReportRows(rows []FlexReportRow, out interface{}) error {
+	slice := reflect.ValueOf(out)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice || slice.Elem().Type().Elem().Kind() != reflect.Struct {
+		return errors.New("out must be a pointer to a slice of structs")
+	}
+	slice = slice.Elem()
+	itemType := slice.Type().Elem()
+
+	for i, row := range rows {
+		item := reflect.New(itemType).Elem()
+		for f := 0; f < itemType.NumField(); f++ {
+			column := itemType.Field(f).Tag.Get("flex")
+			if column == "" {
+				continue
+			}
+
+			value, ok := row[column]
+			if !ok || value == "" {
+				continue
+			}
+
+			err := setFlexReportField(item.Field(f), value)
+			if err != nil {
+				return fmt.Errorf("row %d, column `%s`: %s", i, column, err)
+			}
+		}
+		slice.Set(reflect.Append(slice, item))
+	}
+
+	return nil
+}
+
+// setFlexReportField parses value into field according to the field's type.
+func setFlexReportField(field reflect.Value, value string) error {
+	if field.Type() == reflect.TypeOf(time.Time{}) {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t, err = time.Parse("2006-01-02", value)
+		}
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+
+	return nil
+}
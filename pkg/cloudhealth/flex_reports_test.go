@@ -0,0 +1,139 @@
+package cloudhealth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var defaultFlexReport = FlexReport{
+	Name: "Monthly cost by account",
+	Query: FlexReportQuery{
+		SQLStatement:    BuildFlexReportSQL(FlexReportDatasetAwsCur, []string{"lineItem/UsageAccountId", "SUM(lineItem/UnblendedCost)"}, nil, []string{"lineItem/UsageAccountId"}),
+		DataGranularity: FlexReportGranularityMonthly,
+		TimeRange:       FlexReportTimeRange{Last: 3},
+	},
+}
+
+func TestBuildFlexReportSQL(t *testing.T) {
+	statement := BuildFlexReportSQL(FlexReportDatasetAwsCur, []string{"a", "SUM(b)"}, []string{"a = '1'", "c > 0"}, []string{"a"})
+	assert.Equal(t, "SELECT a, SUM(b) FROM AWS_CUR WHERE a = '1' AND c > 0 GROUP BY a", statement)
+}
+
+func TestFlexReportValidate(t *testing.T) {
+	assert.NoError(t, defaultFlexReport.Validate())
+
+	report := defaultFlexReport
+	report.Query.TimeRange = FlexReportTimeRange{From: "2022-01-01"}
+	assert.Error(t, report.Validate())
+
+	report.Query.TimeRange = FlexReportTimeRange{Last: 1, From: "2022-01-01", To: "2022-02-01"}
+	assert.Error(t, report.Validate())
+}
+
+func TestRunFlexReport(t *testing.T) {
+	polls := 0
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/result.csv" {
+			fmt.Fprint(w, "account_id,cost,day\n123,10.5,2022-01-01\n456,,2022-01-02\n")
+			return
+		}
+
+		var request graphQLRequest
+		err := json.NewDecoder(r.Body).Decode(&request)
+		assert.NoError(t, err)
+
+		switch {
+		case strings.Contains(request.Query, "triggerFlexReportExecution"):
+			fmt.Fprint(w, `{"data": {"triggerFlexReportExecution": {"id": "crn:1:flexreports/1"}}}`)
+		case strings.Contains(request.Query, "node(id: $id)"):
+			polls++
+			status := FlexReportStatusRunning
+			if polls > 1 {
+				status = FlexReportStatusCompleted
+			}
+			fmt.Fprintf(w, `{"data": {"node": {"id": "crn:1:flexreports/1", "result": {"status": "%s", "contents": [{"key": "1", "signedUrl": "%s/result.csv"}]}}}}`, status, ts.URL)
+		default:
+			t.Errorf("Unexpected query ‘%s’", request.Query)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+	c.GraphQLEndpointURL = ts.URL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := c.RunFlexReport(ctx, "crn:1:flexreports/1", 10*time.Millisecond)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, polls)
+	assert.Len(t, rows, 2)
+
+	var typedRows []struct {
+		AccountID string    `flex:"account_id"`
+		Cost      float64   `flex:"cost"`
+		Day       time.Time `flex:"day"`
+	}
+	err = DecodeFlexReportRows(rows, &typedRows)
+	assert.NoError(t, err)
+	assert.Equal(t, "123", typedRows[0].AccountID)
+	assert.Equal(t, 10.5, typedRows[0].Cost)
+	assert.Equal(t, 0.0, typedRows[1].Cost)
+	assert.Equal(t, time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC), typedRows[1].Day)
+}
+
+func TestWaitForFlexReportTimeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": {"node": {"id": "crn:1:flexreports/1", "result": {"status": "RUNNING"}}}}`)
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+	c.GraphQLEndpointURL = ts.URL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = c.WaitForFlexReport(ctx, "crn:1:flexreports/1", 10*time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWaitForFlexReportDefaultInterval(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": {"node": {"id": "crn:1:flexreports/1", "result": {"status": "COMPLETED"}}}}`)
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+	c.GraphQLEndpointURL = ts.URL
+
+	result, err := c.WaitForFlexReport(context.Background(), "crn:1:flexreports/1", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, FlexReportStatusCompleted, result.Status)
+}
+
+func TestGraphQLError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"errors": [{"message": "invalid id"}]}`)
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+	c.GraphQLEndpointURL = ts.URL
+
+	err = c.DeleteFlexReport(context.Background(), "bad")
+	assert.EqualError(t, err, "error from CloudHealth GraphQL API: invalid id")
+}
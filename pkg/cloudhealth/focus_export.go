@@ -0,0 +1,518 @@
+package cloudhealth
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// focusDateFormat is the ISO 8601 format required by FOCUS for date/time columns.
+const focusDateFormat = "2006-01-02T15:04:05Z"
+
+// FOCUS charge categories.
+const (
+	FocusChargeCategoryUsage      = "Usage"
+	FocusChargeCategoryPurchase   = "Purchase"
+	FocusChargeCategoryTax        = "Tax"
+	FocusChargeCategoryCredit     = "Credit"
+	FocusChargeCategoryAdjustment = "Adjustment"
+)
+
+// FocusChargeClassCorrection is the FOCUS charge class of charges correcting a previous billing period.
+const FocusChargeClassCorrection = "Correction"
+
+// FocusColumns lists the FOCUS columns written by WriteFocusCSV, in order.
+var FocusColumns = []string{
+	"BilledCost",
+	"BillingAccountId",
+	"BillingAccountName",
+	"BillingCurrency",
+	"BillingPeriodEnd",
+	"BillingPeriodStart",
+	"ChargeCategory",
+	"ChargeClass",
+	"ChargeDescription",
+	"ChargePeriodEnd",
+	"ChargePeriodStart",
+	"ContractedCost",
+	"EffectiveCost",
+	"InvoiceId",
+	"InvoiceIssuerName",
+	"ListCost",
+	"PricingQuantity",
+	"PricingUnit",
+	"ProviderName",
+	"PublisherName",
+	"RegionId",
+	"ServiceCategory",
+	"ServiceName",
+	"SubAccountId",
+	"SubAccountName",
+	"Tags",
+}
+
+// focusRequiredColumns lists the FOCUS columns that must be present and cannot be empty.
+var focusRequiredColumns = []string{
+	"BilledCost",
+	"BillingAccountId",
+	"BillingCurrency",
+	"BillingPeriodEnd",
+	"BillingPeriodStart",
+	"ChargeCategory",
+	"ChargePeriodEnd",
+	"ChargePeriodStart",
+	"EffectiveCost",
+	"InvoiceIssuerName",
+	"ProviderName",
+	"PublisherName",
+	"ServiceCategory",
+	"ServiceName",
+}
+
+// focusNullableColumns lists the FOCUS columns that must be present but can be empty, when the value is unknown.
+var focusNullableColumns = []string{
+	"ChargeClass",
+	"ContractedCost",
+	"ListCost",
+	"PricingQuantity",
+	"PricingUnit",
+}
+
+// focusProviderNames maps cloud providers to the provider names used in FOCUS.
+var focusProviderNames = map[CloudProvider]string{
+	ProviderAWS:   "Amazon Web Services",
+	ProviderAzure: "Microsoft",
+	ProviderGCP:   "Google Cloud",
+}
+
+// focusServiceNames maps the names of well-known services to FOCUS service categories.
+var focusServiceNames = map[string]string{
+	"ec2 - compute":       "Compute",
+	"ec2 - ebs":           "Storage",
+	"ec2 - data transfer": "Networking",
+	"ec2 - elb":           "Networking",
+	"ec2 - other":         "Compute",
+	"lambda":              "Compute",
+	"rds":                 "Databases",
+	"dynamodb":            "Databases",
+	"s3":                  "Storage",
+	"cloudfront":          "Networking",
+}
+
+// focusServiceCategories maps keywords found in service names to FOCUS service categories, checked in order,
+// so that specific keywords such as "ebs" take precedence over broad ones such as "ec2".
+var focusServiceCategories = []struct {
+	keyword  string
+	category string
+}{
+	{"data transfer", "Networking"},
+	{"network", "Networking"},
+	{"cloudfront", "Networking"},
+	{"vpc", "Networking"},
+	{"load balanc", "Networking"},
+	{"elb", "Networking"},
+	{"ebs", "Storage"},
+	{"snapshot", "Storage"},
+	{"rds", "Databases"},
+	{"database", "Databases"},
+	{"sql", "Databases"},
+	{"dynamo", "Databases"},
+	{"s3", "Storage"},
+	{"storage", "Storage"},
+	{"lambda", "Compute"},
+	{"function", "Compute"},
+	{"ec2", "Compute"},
+	{"compute", "Compute"},
+	{"virtual machine", "Compute"},
+}
+
+var focusCurrencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// FocusRecord represents a row of cost data in the FinOps FOCUS format.
+// ContractedCost, ListCost and PricingQuantity are nil, and written as null, when unknown.
+type FocusRecord struct {
+	BilledCost         float64
+	BillingAccountID   string
+	BillingAccountName string
+	BillingCurrency    string
+	BillingPeriodEnd   time.Time
+	BillingPeriodStart time.Time
+	ChargeCategory     string
+	ChargeClass        string
+	ChargeDescription  string
+	ChargePeriodEnd    time.Time
+	ChargePeriodStart  time.Time
+	ContractedCost     *float64
+	EffectiveCost      float64
+	InvoiceID          string
+	InvoiceIssuerName  string
+	ListCost           *float64
+	PricingQuantity    *float64
+	PricingUnit        string
+	ProviderName       string
+	PublisherName      string
+	RegionID           string
+	ServiceCategory    string
+	ServiceName        string
+	SubAccountID       string
+	SubAccountName     string
+	Tags               map[string]string
+}
+
+// FocusValidationError represents a FOCUS compliance problem in a row, or in the header when Row is 0.
+type FocusValidationError struct {
+	Row     int
+	Column  string
+	Message string
+}
+
+// Error returns the description of the problem.
+func (e FocusValidationError) Error() string {
+	if e.Row == 0 {
+		return fmt.Sprintf("column `%s`: %s", e.Column, e.Message)
+	}
+
+	return fmt.Sprintf("row %d, column `%s`: %s", e.Row, e.Column, e.Message)
+}
+
+// FocusValidationErrors represents all FOCUS compliance problems found in a dataset.
+type FocusValidationErrors []FocusValidationError
+
+// Error returns the description of all problems.
+func (e FocusValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return fmt.Sprintf("%d FOCUS validation errors: %s", len(e), strings.Join(messages, "; "))
+}
+
+// FocusRecordsFromCostRecords converts normalized cost records into FOCUS records. Records without
+// a period are given the billing period when it is not zero.
+func FocusRecordsFromCostRecords(records []CostRecord, billingPeriodStart time.Time, billingPeriodEnd time.Time) []FocusRecord {
+	focusRecords := make([]FocusRecord, 0, len(records))
+	for _, record := range records {
+		providerName := focusProviderNames[record.Provider]
+		if providerName == "" {
+			providerName = string(record.Provider)
+		}
+
+		// FOCUS periods are exclusive of their end, CostRecord periods are inclusive
+		chargeStart, chargeEnd := record.PeriodStart, record.PeriodEnd.Add(time.Nanosecond)
+		if record.PeriodStart.IsZero() {
+			chargeStart, chargeEnd = billingPeriodStart, billingPeriodEnd
+		}
+
+		periodStart, periodEnd := billingPeriodStart, billingPeriodEnd
+		if periodStart.IsZero() {
+			periodStart = time.Date(chargeStart.Year(), chargeStart.Month(), 1, 0, 0, 0, 0, time.UTC)
+			periodEnd = periodStart.AddDate(0, 1, 0)
+		}
+
+		focusRecords = append(focusRecords, FocusRecord{
+			BilledCost:         record.Amount,
+			BillingAccountID:   record.AccountID,
+			BillingAccountName: record.AccountName,
+			BillingCurrency:    record.Currency,
+			BillingPeriodStart: periodStart,
+			BillingPeriodEnd:   periodEnd,
+			ChargeCategory:     FocusChargeCategoryUsage,
+			ChargePeriodStart:  chargeStart,
+			ChargePeriodEnd:    chargeEnd,
+			EffectiveCost:      record.Amount,
+			InvoiceIssuerName:  providerName,
+			ProviderName:       providerName,
+			PublisherName:      providerName,
+			RegionID:           record.Region,
+			ServiceCategory:    focusServiceCategory(record.Service),
+			ServiceName:        record.Service,
+			SubAccountID:       record.AccountID,
+			SubAccountName:     record.AccountName,
+			Tags:               record.Tags,
+		})
+	}
+
+	return focusRecords
+}
+
+// FocusRecordsFromAWSCostHistoryReport converts a measure of an AWS Cost History Report covering
+// the given billing period into FOCUS records, attributed to the billing account.
+func FocusRecordsFromAWSCostHistoryReport(report *AWSCostHistoryReport, measure string, billingAccountID string, billingPeriodStart time.Time, billingPeriodEnd time.Time) ([]FocusRecord, error) {
+	records, err := NormalizeAWSCostHistoryReport(report, measure, "", billingPeriodStart, billingPeriodEnd.Add(-time.Nanosecond))
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range records {
+		records[i].AccountID = billingAccountID
+	}
+
+	return FocusRecordsFromCostRecords(records, billingPeriodStart, billingPeriodEnd), nil
+}
+
+// FocusRecordFromBillingArtifact converts a Customer Statement into a single FOCUS record for its total amount.
+func FocusRecordFromBillingArtifact(artifact BillingArtifact) (FocusRecord, error) {
+	periodStart, err := time.Parse("2006-01-02", artifact.BillingPeriod)
+	if err != nil {
+		periodStart, err = time.Parse("2006-01", artifact.BillingPeriod)
+	}
+	if err != nil {
+		return FocusRecord{}, fmt.Errorf("invalid billing period `%s`: %s", artifact.BillingPeriod, err)
+	}
+	periodStart = time.Date(periodStart.Year(), periodStart.Month(), 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := periodStart.AddDate(0, 1, 0)
+
+	providerName := focusProviderNames[CloudProvider(artifact.CloudProvider)]
+	if providerName == "" {
+		providerName = artifact.CloudProvider
+	}
+
+	return FocusRecord{
+		BilledCost:         artifact.TotalAmount,
+		BillingAccountID:   strconv.Itoa(artifact.CustomerID),
+		BillingCurrency:    artifact.Currency.Name,
+		BillingPeriodStart: periodStart,
+		BillingPeriodEnd:   periodEnd,
+		ChargeCategory:     FocusChargeCategoryUsage,
+		ChargeDescription:  "Customer statement total",
+		ChargePeriodStart:  periodStart,
+		ChargePeriodEnd:    periodEnd,
+		EffectiveCost:      artifact.TotalAmount,
+		InvoiceID:          artifact.InvoiceId,
+		InvoiceIssuerName:  providerName,
+		ProviderName:       providerName,
+		PublisherName:      providerName,
+		ServiceName:        artifact.CloudProvider,
+		ServiceCategory:    "Other",
+	}, nil
+}
+
+// row returns the FOCUS columns of the record as strings.
+func (r FocusRecord) row() map[string]string {
+	tags := ""
+	if len(r.Tags) > 0 {
+		encoded, _ := json.Marshal(r.Tags)
+		tags = string(encoded)
+	}
+
+	return map[string]string{
+		"BilledCost":         strconv.FormatFloat(r.BilledCost, 'f', -1, 64),
+		"BillingAccountId":   r.BillingAccountID,
+		"BillingAccountName": r.BillingAccountName,
+		"BillingCurrency":    r.BillingCurrency,
+		"BillingPeriodEnd":   focusDate(r.BillingPeriodEnd),
+		"BillingPeriodStart": focusDate(r.BillingPeriodStart),
+		"ChargeCategory":     r.ChargeCategory,
+		"ChargeClass":        r.ChargeClass,
+		"ChargeDescription":  r.ChargeDescription,
+		"ChargePeriodEnd":    focusDate(r.ChargePeriodEnd),
+		"ChargePeriodStart":  focusDate(r.ChargePeriodStart),
+		"ContractedCost":     focusDecimal(r.ContractedCost),
+		"EffectiveCost":      strconv.FormatFloat(r.EffectiveCost, 'f', -1, 64),
+		"InvoiceId":          r.InvoiceID,
+		"InvoiceIssuerName":  r.InvoiceIssuerName,
+		"ListCost":           focusDecimal(r.ListCost),
+		"PricingQuantity":    focusDecimal(r.PricingQuantity),
+		"PricingUnit":        r.PricingUnit,
+		"ProviderName":       r.ProviderName,
+		"PublisherName":      r.PublisherName,
+		"RegionId":           r.RegionID,
+		"ServiceCategory":    r.ServiceCategory,
+		"ServiceName":        r.ServiceName,
+		"SubAccountId":       r.SubAccountID,
+		"SubAccountName":     r.SubAccountName,
+		"Tags":               tags,
+	}
+}
+
+// WriteFocusCSV writes the records as CSV with a header of FocusColumns.
+func WriteFocusCSV(w io.Writer, records []FocusRecord) error {
+	writer := csv.NewWriter(w)
+
+	err := writer.Write(FocusColumns)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		row := record.row()
+		values := make([]string, len(FocusColumns))
+		for i, column := range FocusColumns {
+			values[i] = row[column]
+		}
+
+		err = writer.Write(values)
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ValidateFocusRecords checks that the records are FOCUS compliant.
+// The returned error is a FocusValidationErrors listing every problem.
+func ValidateFocusRecords(records []FocusRecord) error {
+	rows := make([]map[string]string, len(records))
+	for i, record := range records {
+		rows[i] = record.row()
+	}
+
+	return validateFocusRows(FocusColumns, rows)
+}
+
+// ValidateFocusCSV checks that a CSV export, such as one written by WriteFocusCSV, is FOCUS compliant.
+// The returned error is a FocusValidationErrors listing every problem, or the CSV parsing error.
+func ValidateFocusCSV(r io.Reader) error {
+	reader := csv.NewReader(r)
+
+	header, err := reader.Read()
+	if err != nil {
+		return err
+	}
+
+	var rows []map[string]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		row := make(map[string]string, len(header))
+		for i, column := range header {
+			row[column] = record[i]
+		}
+		rows = append(rows, row)
+	}
+
+	return validateFocusRows(header, rows)
+}
+
+// validateFocusRows checks the required columns and the format of the values of each row.
+func validateFocusRows(columns []string, rows []map[string]string) error {
+	var errs FocusValidationErrors
+
+	present := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		present[column] = true
+	}
+	for _, column := range append(append([]string{}, focusRequiredColumns...), focusNullableColumns...) {
+		if !present[column] {
+			errs = append(errs, FocusValidationError{Column: column, Message: "required column is missing"})
+		}
+	}
+
+	for i, row := range rows {
+		fail := func(column string, format string, args ...interface{}) {
+			errs = append(errs, FocusValidationError{Row: i + 1, Column: column, Message: fmt.Sprintf(format, args...)})
+		}
+
+		for _, column := range focusRequiredColumns {
+			if present[column] && row[column] == "" {
+				fail(column, "value is required")
+			}
+		}
+
+		for _, column := range []string{"BilledCost", "ContractedCost", "EffectiveCost", "ListCost", "PricingQuantity"} {
+			if row[column] == "" {
+				continue
+			}
+			if _, err := strconv.ParseFloat(row[column], 64); err != nil {
+				fail(column, "`%s` is not a decimal number", row[column])
+			}
+		}
+
+		dates := map[string]time.Time{}
+		for _, column := range []string{"BillingPeriodStart", "BillingPeriodEnd", "ChargePeriodStart", "ChargePeriodEnd"} {
+			if row[column] == "" {
+				continue
+			}
+			date, err := time.Parse(focusDateFormat, row[column])
+			if err != nil {
+				fail(column, "`%s` is not a UTC date in the %s format", row[column], focusDateFormat)
+				continue
+			}
+			dates[column] = date
+		}
+		for _, period := range []string{"BillingPeriod", "ChargePeriod"} {
+			start, hasStart := dates[period+"Start"]
+			end, hasEnd := dates[period+"End"]
+			if hasStart && hasEnd && !start.Before(end) {
+				fail(period+"End", "must be after %sStart", period)
+			}
+		}
+
+		if currency := row["BillingCurrency"]; currency != "" && !focusCurrencyPattern.MatchString(currency) {
+			fail("BillingCurrency", "`%s` is not an ISO 4217 currency code", currency)
+		}
+
+		switch row["ChargeClass"] {
+		case "", FocusChargeClassCorrection:
+		default:
+			fail("ChargeClass", "`%s` is not a FOCUS charge class", row["ChargeClass"])
+		}
+
+		switch row["ChargeCategory"] {
+		case "", FocusChargeCategoryUsage, FocusChargeCategoryPurchase, FocusChargeCategoryTax, FocusChargeCategoryCredit, FocusChargeCategoryAdjustment:
+		default:
+			fail("ChargeCategory", "`%s` is not a FOCUS charge category", row["ChargeCategory"])
+		}
+
+		if tags := row["Tags"]; tags != "" {
+			var decoded map[string]interface{}
+			if err := json.Unmarshal([]byte(tags), &decoded); err != nil {
+				fail("Tags", "value is not a JSON object")
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// focusDate formats a date for FOCUS, leaving zero dates empty.
+func focusDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(focusDateFormat)
+}
+
+// focusDecimal formats an optional decimal for FOCUS, leaving unknown values empty.
+func focusDecimal(value *float64) string {
+	if value == nil {
+		return ""
+	}
+
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
+
+// focusServiceCategory returns the FOCUS service category of a well-known service, or guesses it from its name.
+func focusServiceCategory(service string) string {
+	name := strings.ToLower(strings.TrimSpace(service))
+	if category, ok := focusServiceNames[name]; ok {
+		return category
+	}
+
+	for _, mapping := range focusServiceCategories {
+		if strings.Contains(name, mapping.keyword) {
+			return mapping.category
+		}
+	}
+
+	return "Other"
+}
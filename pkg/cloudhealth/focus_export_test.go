@@ -0,0 +1,97 @@
+package cloudhealth
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFocusExportFromAWSCostHistoryReport(t *testing.T) {
+	var report AWSCostHistoryReport
+	assert.NoError(t, json.Unmarshal([]byte(currentMonthCostHistoryReport), &report))
+
+	start := time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+	records, err := FocusRecordsFromAWSCostHistoryReport(&report, MeasureCost, "123456789012", start, start.AddDate(0, 1, 0))
+	assert.NoError(t, err)
+	assert.Len(t, records, 3)
+	assert.Equal(t, "Compute", records[0].ServiceCategory)
+	assert.Equal(t, "EC2 - Compute", records[0].ServiceName)
+	assert.Equal(t, "Amazon Web Services", records[0].ProviderName)
+	assert.NoError(t, ValidateFocusRecords(records))
+
+	var buffer bytes.Buffer
+	assert.NoError(t, WriteFocusCSV(&buffer, records))
+	assert.True(t, strings.HasPrefix(buffer.String(), strings.Join(FocusColumns, ",")+"\n"))
+	assert.Contains(t, buffer.String(), "150,123456789012,,USD,2022-03-01T00:00:00Z,2022-02-01T00:00:00Z,Usage,,,2022-03-01T00:00:00Z,2022-02-01T00:00:00Z,,150,")
+	assert.NoError(t, ValidateFocusCSV(&buffer))
+}
+
+func TestFocusExportSkipsParentServiceCategories(t *testing.T) {
+	var report AWSCostHistoryReport
+	assert.NoError(t, json.Unmarshal([]byte(serviceCategoryCostHistoryReport), &report))
+
+	start := time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+	records, err := FocusRecordsFromAWSCostHistoryReport(&report, MeasureCost, "123456789012", start, start.AddDate(0, 1, 0))
+	assert.NoError(t, err)
+
+	total := 0.0
+	for _, record := range records {
+		total += record.BilledCost
+		assert.Nil(t, record.ListCost)
+		assert.Nil(t, record.ContractedCost)
+	}
+	assert.Equal(t, 170.0, total)
+}
+
+func TestFocusServiceCategory(t *testing.T) {
+	assert.Equal(t, "Compute", focusServiceCategory("EC2 - Compute"))
+	assert.Equal(t, "Storage", focusServiceCategory("EC2 - EBS"))
+	assert.Equal(t, "Networking", focusServiceCategory("EC2 - Data Transfer"))
+	assert.Equal(t, "Storage", focusServiceCategory("EC2 - EBS Snapshots"))
+	assert.Equal(t, "Databases", focusServiceCategory("Azure SQL Database"))
+	assert.Equal(t, "Other", focusServiceCategory("Support"))
+}
+
+func TestFocusRecordFromBillingArtifact(t *testing.T) {
+	record, err := FocusRecordFromBillingArtifact(BillingArtifact{
+		CustomerID:    42,
+		CloudProvider: "AWS",
+		BillingPeriod: "2022-01-01",
+		TotalAmount:   9999.99,
+		Currency:      Currency{Name: "USD", Symbol: "$"},
+		InvoiceId:     "INV-1",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "42", record.BillingAccountID)
+	assert.Equal(t, time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC), record.BillingPeriodEnd)
+	assert.NoError(t, ValidateFocusRecords([]FocusRecord{record}))
+
+	_, err = FocusRecordFromBillingArtifact(BillingArtifact{BillingPeriod: "January"})
+	assert.Error(t, err)
+}
+
+func TestValidateFocusCSV(t *testing.T) {
+	csv := "BilledCost,BillingCurrency,ChargeCategory,Tags,BillingPeriodStart,BillingPeriodEnd\n" +
+		"abc,usd,Refund,not-json,2022-02-01T00:00:00Z,2022-01-01\n"
+
+	err := ValidateFocusCSV(strings.NewReader(csv))
+	errs, ok := err.(FocusValidationErrors)
+	assert.True(t, ok)
+
+	messages := make([]string, len(errs))
+	for i, e := range errs {
+		messages[i] = e.Error()
+	}
+	assert.Contains(t, messages, "column `BillingAccountId`: required column is missing")
+	assert.Contains(t, messages, "column `ServiceCategory`: required column is missing")
+	assert.Contains(t, messages, "column `ListCost`: required column is missing")
+	assert.Contains(t, messages, "row 1, column `BilledCost`: `abc` is not a decimal number")
+	assert.Contains(t, messages, "row 1, column `BillingPeriodEnd`: `2022-01-01` is not a UTC date in the 2006-01-02T15:04:05Z format")
+	assert.Contains(t, messages, "row 1, column `BillingCurrency`: `usd` is not an ISO 4217 currency code")
+	assert.Contains(t, messages, "row 1, column `ChargeCategory`: `Refund` is not a FOCUS charge category")
+	assert.Contains(t, messages, "row 1, column `Tags`: value is not a JSON object")
+}
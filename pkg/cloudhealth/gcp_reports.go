@@ -0,0 +1,44 @@
+package cloudhealth
+
+import "context"
+
+// OLAPReportGCPCostHistory is the path of the GCP cost history OLAP report.
+const OLAPReportGCPCostHistory = "gcp_cost/history"
+
+// Dimensions available in GCP cost reports.
+const (
+	DimensionGCPService = "GCP-Service"
+	DimensionGCPProject = "GCP-Project"
+	DimensionGCPRegion  = "GCP-Region"
+)
+
+// GCPCostHistoryReport represents the details of a Cost History Report for GCP in CloudHealth.
+type GCPCostHistoryReport struct {
+	OLAPReport
+}
+
+// GetGCPCostHistoryReport gets the GCP Cost History Report. The cost measure and the
+// GCP-Service dimension are used when no measures or dimensions are specified.
+func (s *Client) GetGCPCostHistoryReport(requestOptions *OLAPReportRequestOptions) (*GCPCostHistoryReport, error) {
+	return s.GetGCPCostHistoryReportWithContext(context.Background(), requestOptions)
+}
+
+// GetGCPCostHistoryReportWithContext gets the GCP Cost History Report, bound to the given context.
+func (s *Client) GetGCPCostHistoryReportWithContext(ctx context.Context, requestOptions *OLAPReportRequestOptions) (*GCPCostHistoryReport, error) {
+	report, err := s.GetOLAPReportWithContext(ctx, OLAPReportGCPCostHistory, withCostHistoryDefaults(requestOptions, DimensionGCPService))
+	if err != nil {
+		return nil, err
+	}
+
+	return &GCPCostHistoryReport{OLAPReport: *report}, nil
+}
+
+// Services returns the GCP services of the report, if grouped by service.
+func (r *GCPCostHistoryReport) Services() []OLAPDimensionMember {
+	return r.dimensionMembers(DimensionGCPService)
+}
+
+// Projects returns the GCP projects of the report, if grouped by project.
+func (r *GCPCostHistoryReport) Projects() []OLAPDimensionMember {
+	return r.dimensionMembers(DimensionGCPProject)
+}
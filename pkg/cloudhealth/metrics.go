@@ -0,0 +1,425 @@
+package cloudhealth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ErrMetricsUploadFailed is returned when at least one batch of a metrics upload was rejected.
+var ErrMetricsUploadFailed = errors.New("metrics upload failed")
+
+// Limits of a single request to the Metrics API.
+const (
+	MetricsMaxRowsPerRequest = 1000
+	MetricsMaxRequestBytes   = 1 << 20
+)
+
+// MetricsGranularity is the period covered by each datapoint of a metrics dataset.
+type MetricsGranularity string
+
+// Granularities supported by the Metrics API.
+const (
+	MetricsGranularityHour MetricsGranularity = "hour"
+	MetricsGranularityDay  MetricsGranularity = "day"
+)
+
+// Keys every metrics dataset starts with, before its metric keys.
+const (
+	MetricKeyAssetID   = "assetId"
+	MetricKeyTimestamp = "timestamp"
+)
+
+// MetricsDatasetMetadata describes the asset type, granularity and columns of a metrics dataset.
+// Keys are assetId, timestamp, then metric keys such as `cpu:used:percent.avg`.
+type MetricsDatasetMetadata struct {
+	AssetType   string             `json:"assetType"`
+	Granularity MetricsGranularity `json:"granularity"`
+	Keys        []string           `json:"keys"`
+}
+
+// MetricsDataset represents datapoints of several assets for the same metrics.
+type MetricsDataset struct {
+	Metadata MetricsDatasetMetadata `json:"metadata"`
+	Data     [][]interface{}        `json:"data"`
+}
+
+// MetricsUploadOptions configures how datasets are uploaded. Zero values use the API limits.
+type MetricsUploadOptions struct {
+	MaxRowsPerRequest int
+	MaxRequestBytes   int
+	DryRun            bool
+}
+
+// MetricsBatchResult represents the outcome of one request of a metrics upload.
+// Batch and Rows are set by the client and never read from the response.
+type MetricsBatchResult struct {
+	Batch     int      `json:"-"`
+	Rows      int      `json:"-"`
+	Succeeded int      `json:"succeeded"`
+	Failed    int      `json:"failed"`
+	Errors    []string `json:"errors"`
+	Err       error    `json:"-"`
+}
+
+// metricsUploadRequest is the body of a request to the Metrics API.
+type metricsUploadRequest struct {
+	Metrics struct {
+		Datasets []MetricsDataset `json:"datasets"`
+	} `json:"metrics"`
+}
+
+// NewMetricsDataset returns an empty MetricsDataset for the asset type, such as `aws:ec2:instance`, and metric keys.
+func NewMetricsDataset(assetType string, granularity MetricsGranularity, metricKeys ...string) *MetricsDataset {
+	return &MetricsDataset{
+		Metadata: MetricsDatasetMetadata{
+			AssetType:   assetType,
+			Granularity: granularity,
+			Keys:        append([]string{MetricKeyAssetID, MetricKeyTimestamp}, metricKeys...),
+		},
+	}
+}
+
+// AddDatapoint adds the values of the metric keys, in order, for the asset at timestamp.
+func (d *MetricsDataset) AddDatapoint(assetID string, timestamp time.Time, values ...float64) *MetricsDataset {
+	row := []interface{}{assetID, timestamp.UTC().Format(time.RFC3339)}
+	for _, value := range values {
+		row = append(row, value)
+	}
+
+	d.Data = append(d.Data, row)
+	return d
+}
+
+// Validate checks the shape of the dataset: its metadata, and that every row has an asset, a timestamp
+// and a numeric value for each metric key.
+func (d *MetricsDataset) Validate() error {
+	metadata := d.Metadata
+	if metadata.AssetType == "" {
+		return errors.New("metrics dataset has no asset type")
+	}
+	if metadata.Granularity != MetricsGranularityHour && metadata.Granularity != MetricsGranularityDay {
+		return fmt.Errorf("invalid metrics granularity `%s`", metadata.Granularity)
+	}
+	if len(metadata.Keys) < 3 || metadata.Keys[0] != MetricKeyAssetID || metadata.Keys[1] != MetricKeyTimestamp {
+		return fmt.Errorf("metrics keys must start with `%s` and `%s` followed by at least one metric", MetricKeyAssetID, MetricKeyTimestamp)
+	}
+
+	seen := make(map[string]bool, len(metadata.Keys))
+	for _, key := range metadata.Keys {
+		if key == "" || seen[key] {
+			return fmt.Errorf("metrics key `%s` is empty or duplicated", key)
+		}
+		seen[key] = true
+	}
+
+	for i, row := range d.Data {
+		if len(row) != len(metadata.Keys) {
+			return fmt.Errorf("metrics row %d has %d values, expected %d", i, len(row), len(metadata.Keys))
+		}
+		if assetID, ok := row[0].(string); !ok || assetID == "" {
+			return fmt.Errorf("metrics row %d has an invalid asset id", i)
+		}
+		timestamp, ok := row[1].(string)
+		if !ok {
+			return fmt.Errorf("metrics row %d has an invalid timestamp", i)
+		}
+		if _, err := time.Parse(time.RFC3339, timestamp); err != nil {
+			return fmt.Errorf("metrics row %d has an invalid timestamp: %s", i, err)
+		}
+		for j, value := range row[2:] {
+			switch value.(type) {
+			case float64, float32, int, int64:
+			default:
+				return fmt.Errorf("metrics row %d has a non numeric value for `%s`", i, metadata.Keys[j+2])
+			}
+		}
+	}
+
+	return nil
+}
+
+// batchMetricsDatasets splits the datasets into batches of at most maxRows rows and about maxBytes bytes once encoded.
+func batchMetricsDatasets(datasets []MetricsDataset, maxRows int, maxBytes int) ([][]MetricsDataset, error) {
+	var batches [][]MetricsDataset
+	var batch []MetricsDataset
+	var rows, size int
+
+	flush := func() {
+		if rows > 0 {
+			batches = append(batches, batch)
+		}
+		batch, rows, size = nil, 0, 0
+	}
+
+	for _, dataset := range datasets {
+		metadata, _ := json.Marshal(dataset.Metadata)
+		overhead := len(metadata) + len(`{"metadata":,"data":[]},`)
+		started := false
+
+		for _, row := range dataset.Data {
+			encoded, err := json.Marshal(row)
+			if err != nil {
+				return nil, err
+			}
+
+			rowSize := len(encoded) + 1
+			if rowSize+overhead+len(`{"metrics":{"datasets":[]}}`) > maxBytes {
+				return nil, fmt.Errorf("metrics row of `%s` is larger than the request size limit", dataset.Metadata.AssetType)
+			}
+
+			needed := rowSize
+			if !started {
+				needed += overhead
+			}
+			if rows+1 > maxRows || size+needed > maxBytes {
+				flush()
+				started = false
+			}
+			if !started {
+				batch = append(batch, MetricsDataset{Metadata: dataset.Metadata})
+				size += overhead
+				started = true
+			}
+
+			current := &batch[len(batch)-1]
+			current.Data = append(current.Data, row)
+			rows++
+			size += rowSize
+		}
+	}
+	flush()
+
+	return batches, nil
+}
+
+// UploadMetrics validates the datasets, splits them into batches within the request limits and uploads them.
+// Every batch is attempted; the result of each one is returned, with ErrMetricsUploadFailed when any of them failed.
+func (s *Client) UploadMetrics(ctx context.Context, datasets []MetricsDataset, options *MetricsUploadOptions) ([]MetricsBatchResult, error) {
+	if options == nil {
+		options = &MetricsUploadOptions{}
+	}
+	maxRows := options.MaxRowsPerRequest
+	if maxRows <= 0 || maxRows > MetricsMaxRowsPerRequest {
+		maxRows = MetricsMaxRowsPerRequest
+	}
+	maxBytes := options.MaxRequestBytes
+	if maxBytes <= 0 || maxBytes > MetricsMaxRequestBytes {
+		maxBytes = MetricsMaxRequestBytes
+	}
+
+	for i := range datasets {
+		err := datasets[i].Validate()
+		if err != nil {
+			return nil, fmt.Errorf("metrics dataset %d: %w", i, err)
+		}
+	}
+
+	batches, err := batchMetricsDatasets(datasets, maxRows, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set up the URL
+	relativeURL := "metrics/v1"
+	if options.DryRun {
+		relativeURL += "?dryrun=true"
+	}
+
+	results := make([]MetricsBatchResult, len(batches))
+	failed := 0
+	for i, batch := range batches {
+		result := &results[i]
+		result.Batch = i
+		for _, dataset := range batch {
+			result.Rows += len(dataset.Data)
+		}
+
+		var request metricsUploadRequest
+		request.Metrics.Datasets = batch
+
+		// Make the API call
+		responseBody, err := createResourceWithContext(ctx, s, relativeURL, request)
+		if err == nil && len(responseBody) > 0 {
+			// Unmarshal the response data into the MetricsBatchResult struct
+			err = json.Unmarshal(responseBody, result)
+		}
+		if err == nil && (result.Failed > 0 || len(result.Errors) > 0) {
+			err = fmt.Errorf("%d of %d rows rejected", result.Failed, result.Rows)
+		}
+
+		if err != nil {
+			result.Err = err
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return results, fmt.Errorf("%w: %d of %d batches", ErrMetricsUploadFailed, failed, len(batches))
+	}
+
+	return results, nil
+}
+
+// metricsPageSize is the number of rows requested per page of metrics.
+const metricsPageSize = 100
+
+// MetricsQuery selects the metrics to read back: their granularity, time window and, optionally, metric keys.
+// A zero From or To leaves that side of the window to the API default.
+type MetricsQuery struct {
+	Granularity MetricsGranularity
+	From        time.Time
+	To          time.Time
+	Metrics     []string
+}
+
+// MetricPoint represents the value of a metric at a timestamp.
+type MetricPoint struct {
+	Timestamp time.Time
+	Value     float64
+}
+
+// AssetMetrics represents the time series of each metric of an asset, keyed by metric, in timestamp order.
+type AssetMetrics struct {
+	AssetID     string
+	Granularity MetricsGranularity
+	Series      map[string][]MetricPoint
+}
+
+// metricsResponse is the body of a response of the Metrics API.
+type metricsResponse struct {
+	Metrics struct {
+		Datasets []MetricsDataset `json:"datasets"`
+	} `json:"metrics"`
+}
+
+// GetAssetMetrics gets the metrics CloudHealth holds for the asset, such as `us-east-1:123456789012:i-0123`.
+func (s *Client) GetAssetMetrics(ctx context.Context, assetID string, query *MetricsQuery) (*AssetMetrics, error) {
+	if query == nil {
+		query = &MetricsQuery{Granularity: MetricsGranularityHour}
+	}
+	if query.Granularity != MetricsGranularityHour && query.Granularity != MetricsGranularityDay {
+		return nil, fmt.Errorf("invalid metrics granularity `%s`", query.Granularity)
+	}
+
+	wanted := make(map[string]bool, len(query.Metrics))
+	for _, metric := range query.Metrics {
+		wanted[metric] = true
+	}
+
+	// Set variables we will need along the way
+	metrics := &AssetMetrics{AssetID: assetID, Granularity: query.Granularity, Series: make(map[string][]MetricPoint)}
+	var page, pageSize int = 1, metricsPageSize
+
+	// Loop for paging
+	for {
+		// Set up the query parameters for the API
+		params := url.Values{
+			"asset":       {assetID},
+			"granularity": {string(query.Granularity)},
+			"page":        {strconv.Itoa(page)},
+			"per_page":    {strconv.Itoa(pageSize)},
+		}
+		if !query.From.IsZero() {
+			params.Set("from", query.From.UTC().Format(time.RFC3339))
+		}
+		if !query.To.IsZero() {
+			params.Set("to", query.To.UTC().Format(time.RFC3339))
+		}
+
+		// Set up the URL
+		relativeURL := fmt.Sprintf("metrics/v1?%s", params.Encode())
+
+		// Make the API call
+		responseBody, err := getResponsePageWithContext(ctx, s, relativeURL)
+		if err != nil {
+			return nil, err
+		}
+
+		// Unmarshal the response data into the metricsResponse struct
+		var response metricsResponse
+		err = json.Unmarshal(responseBody, &response)
+		if err != nil {
+			return nil, err
+		}
+
+		rows := 0
+		for _, dataset := range response.Metrics.Datasets {
+			rows += len(dataset.Data)
+			err = metrics.add(dataset, wanted)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// Check length of the page to determine if we should break out of the loop
+		if rows < pageSize {
+			break
+		}
+
+		// Increment page counter
+		page++
+	}
+
+	for metric := range metrics.Series {
+		points := metrics.Series[metric]
+		sort.SliceStable(points, func(i, j int) bool {
+			return points[i].Timestamp.Before(points[j].Timestamp)
+		})
+	}
+
+	return metrics, nil
+}
+
+// GetMetrics gets the metrics CloudHealth holds for each of the assets, keyed by asset.
+func (s *Client) GetMetrics(ctx context.Context, assetIDs []string, query *MetricsQuery) (map[string]*AssetMetrics, error) {
+	metrics := make(map[string]*AssetMetrics, len(assetIDs))
+	for _, assetID := range assetIDs {
+		assetMetrics, err := s.GetAssetMetrics(ctx, assetID, query)
+		if err != nil {
+			return nil, fmt.Errorf("metrics of asset `%s`: %w", assetID, err)
+		}
+		metrics[assetID] = assetMetrics
+	}
+
+	return metrics, nil
+}
+
+// add adds the datapoints of the dataset to the series of the wanted metrics, or of all metrics when none are wanted.
+// Missing values are skipped.
+func (m *AssetMetrics) add(dataset MetricsDataset, wanted map[string]bool) error {
+	keys := dataset.Metadata.Keys
+	if len(keys) < 2 || keys[0] != MetricKeyAssetID || keys[1] != MetricKeyTimestamp {
+		return fmt.Errorf("unexpected metrics keys %v", keys)
+	}
+
+	for _, row := range dataset.Data {
+		if len(row) != len(keys) {
+			return fmt.Errorf("metrics row has %d values, expected %d", len(row), len(keys))
+		}
+
+		text, _ := row[1].(string)
+		timestamp, err := time.Parse(time.RFC3339, text)
+		if err != nil {
+			return err
+		}
+
+		for i, key := range keys[2:] {
+			if len(wanted) > 0 && !wanted[key] {
+				continue
+			}
+			value, ok := row[i+2].(float64)
+			if !ok {
+				continue
+			}
+			m.Series[key] = append(m.Series[key], MetricPoint{Timestamp: timestamp, Value: value})
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package cloudhealth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsDatasetValidate(t *testing.T) {
+	start := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	dataset := NewMetricsDataset("aws:ec2:instance", MetricsGranularityHour, "cpu:used:percent.avg").AddDatapoint("us-east-1:123456789012:i-0123", start, 12.5)
+	assert.NoError(t, dataset.Validate())
+
+	dataset.Data = append(dataset.Data, []interface{}{"us-east-1:123456789012:i-0123", "yesterday", 1.0})
+	assert.EqualError(t, dataset.Validate(), `metrics row 1 has an invalid timestamp: parsing time "yesterday" as "2006-01-02T15:04:05Z07:00": cannot parse "yesterday" as "2006"`)
+
+	dataset.Data[1] = []interface{}{"us-east-1:123456789012:i-0123", "2022-03-01T01:00:00Z"}
+	assert.EqualError(t, dataset.Validate(), "metrics row 1 has 2 values, expected 3")
+
+	assert.EqualError(t, NewMetricsDataset("aws:ec2:instance", "minute", "cpu").Validate(), "invalid metrics granularity `minute`")
+	assert.Error(t, NewMetricsDataset("aws:ec2:instance", MetricsGranularityDay).Validate())
+}
+
+func TestBatchMetricsDatasetsFillsBatches(t *testing.T) {
+	start := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	cpu := NewMetricsDataset("aws:ec2:instance", MetricsGranularityHour, "cpu:used:percent.avg")
+	memory := NewMetricsDataset("aws:ec2:instance", MetricsGranularityHour, "mem:used:percent.avg")
+	for i := 0; i < 3; i++ {
+		cpu.AddDatapoint("i-1", start.Add(time.Duration(i)*time.Hour), 1.0)
+		memory.AddDatapoint("i-1", start.Add(time.Duration(i)*time.Hour), 1.0)
+	}
+
+	metadata, _ := json.Marshal(cpu.Metadata)
+	overhead := len(metadata) + len(`{"metadata":,"data":[]},`)
+	row, _ := json.Marshal(cpu.Data[0])
+
+	// The metadata of a dataset starting a new batch is counted once
+	batches, err := batchMetricsDatasets([]MetricsDataset{*cpu, *memory}, MetricsMaxRowsPerRequest, overhead+3*(len(row)+1))
+	assert.NoError(t, err)
+
+	rows := make([]int, len(batches))
+	for i, batch := range batches {
+		for _, dataset := range batch {
+			rows[i] += len(dataset.Data)
+		}
+	}
+	assert.Equal(t, []int{3, 3}, rows)
+}
+
+func TestUploadMetrics(t *testing.T) {
+	var batchRows []int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/metrics/v1", r.URL.EscapedPath())
+		assert.Equal(t, "true", r.URL.Query().Get("dryrun"))
+
+		var request metricsUploadRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		rows := 0
+		for _, dataset := range request.Metrics.Datasets {
+			rows += len(dataset.Data)
+		}
+		batchRows = append(batchRows, rows)
+
+		// Reject a row of the second batch
+		if len(batchRows) == 2 {
+			w.Write([]byte(`{"succeeded": 1, "failed": 1, "errors": ["unknown asset"]}`))
+			return
+		}
+		w.Write([]byte(fmt.Sprintf(`{"batch": 9, "rows": 0, "succeeded": %d, "failed": 0, "errors": []}`, rows)))
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+
+	start := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	cpu := NewMetricsDataset("aws:ec2:instance", MetricsGranularityHour, "cpu:used:percent.avg")
+	memory := NewMetricsDataset("aws:ec2:instance", MetricsGranularityHour, "memory:used:percent.avg")
+	for i := 0; i < 3; i++ {
+		cpu.AddDatapoint("us-east-1:123456789012:i-0123", start.Add(time.Duration(i)*time.Hour), float64(i))
+		memory.AddDatapoint("us-east-1:123456789012:i-0123", start.Add(time.Duration(i)*time.Hour), float64(i))
+	}
+
+	results, err := c.UploadMetrics(context.Background(), []MetricsDataset{*cpu, *memory}, &MetricsUploadOptions{MaxRowsPerRequest: 4, DryRun: true})
+	assert.ErrorIs(t, err, ErrMetricsUploadFailed)
+	assert.Equal(t, []int{4, 2}, batchRows)
+
+	if assert.Len(t, results, 2) {
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, 0, results[0].Batch)
+		assert.Equal(t, 4, results[0].Rows)
+		assert.Equal(t, 4, results[0].Succeeded)
+		assert.EqualError(t, results[1].Err, "1 of 2 rows rejected")
+		assert.Equal(t, []string{"unknown asset"}, results[1].Errors)
+	}
+
+	// Invalid datasets are not uploaded
+	_, err = c.UploadMetrics(context.Background(), []MetricsDataset{*NewMetricsDataset("", MetricsGranularityHour, "cpu")}, nil)
+	assert.EqualError(t, err, "metrics dataset 0: metrics dataset has no asset type")
+	assert.Len(t, batchRows, 2)
+}
+
+func TestGetMetrics(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/metrics/v1", r.URL.EscapedPath())
+		assert.Equal(t, "hour", r.URL.Query().Get("granularity"))
+		assert.Equal(t, "2022-03-01T00:00:00Z", r.URL.Query().Get("from"))
+
+		// Return a full first page and a partial second page
+		asset := r.URL.Query().Get("asset")
+		dataset := NewMetricsDataset("aws:ec2:instance", MetricsGranularityHour, "cpu:used:percent.avg", "memory:used:percent.avg")
+		start := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+		count := metricsPageSize
+		if r.URL.Query().Get("page") == "2" {
+			start = start.Add(time.Duration(metricsPageSize) * time.Hour)
+			count = 2
+		}
+		for i := count - 1; i >= 0; i-- {
+			dataset.AddDatapoint(asset, start.Add(time.Duration(i)*time.Hour), float64(i), 50)
+		}
+		dataset.Data[0][3] = nil
+
+		var response metricsResponse
+		response.Metrics.Datasets = []MetricsDataset{*dataset}
+		body, _ := json.Marshal(response)
+		w.Write(body)
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+
+	query := &MetricsQuery{Granularity: MetricsGranularityHour, From: time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC), Metrics: []string{"cpu:used:percent.avg"}}
+	metrics, err := c.GetMetrics(context.Background(), []string{"i-1", "i-2"}, query)
+	assert.NoError(t, err)
+	assert.Len(t, metrics, 2)
+
+	series := metrics["i-2"].Series
+	assert.Len(t, series, 1)
+	if assert.Len(t, series["cpu:used:percent.avg"], metricsPageSize+2) {
+		assert.Equal(t, MetricPoint{Timestamp: time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC), Value: 0}, series["cpu:used:percent.avg"][0])
+	}
+
+	_, err = c.GetAssetMetrics(context.Background(), "i-1", &MetricsQuery{Granularity: "week"})
+	assert.EqualError(t, err, "invalid metrics granularity `week`")
+}
@@ -0,0 +1,361 @@
+package cloudhealth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// OLAP report paths, relative to `olap_reports/`.
+const (
+	OLAPReportCostHistory = "cost/history"
+	OLAPReportCostCurrent = "cost/current"
+)
+
+// Measures available in cost reports.
+const (
+	MeasureCost          = "cost"
+	MeasureProjectedCost = "projected_cost"
+)
+
+// Dimensions available in AWS cost reports.
+const (
+	DimensionTime               = "time"
+	DimensionAWSServiceCategory = "AWS-Service-Category"
+	DimensionAWSAccount         = "AWS-Account"
+	DimensionAWSRegion          = "AWS-Region"
+)
+
+// TotalMember is the name of the dimension member aggregating all other members of the dimension.
+const TotalMember = "total"
+
+// OLAPDimensionMember represents a member of any dimension of an OLAP report.
+type OLAPDimensionMember = AWSCostHistoryReportAwsServiceCategory
+
+// OLAPReportMeasure represents a measure of any OLAP report.
+type OLAPReportMeasure = AWSCostHistoryReportMeasures
+
+// OLAPFilter represents a filter on a dimension of an OLAP report.
+type OLAPFilter struct {
+	Dimension string
+	Reject    bool
+	Members   []string
+}
+
+// String returns the filter in CloudHealth's `dimension:select:members` format.
+func (f OLAPFilter) String() string {
+	operation := "select"
+	if f.Reject {
+		operation = "reject"
+	}
+
+	return fmt.Sprintf("%s:%s:%s", f.Dimension, operation, strings.Join(f.Members, ","))
+}
+
+// OLAPReportRequestOptions represents the options to specify when making a request against any OLAP report.
+type OLAPReportRequestOptions struct {
+	Interval    Interval
+	TimeRange   TimeRange
+	Measures    []string
+	Dimensions  []string
+	Filters     []OLAPFilter
+	ClientAPIID string
+}
+
+// OLAPReport represents the details of any OLAP report in CloudHealth.
+type OLAPReport struct {
+	BillDropInfo         BillDrops           `json:"bill_drop_info"`
+	CubeID               string              `json:"cube_id"`
+	Data                 json.RawMessage     `json:"data"`
+	Dimensions           []OLAPDimension     `json:"dimensions"`
+	EnableDpPopover      bool                `json:"enable_dp_popover"`
+	Filters              []string            `json:"filters"`
+	Interval             string              `json:"interval"`
+	Measures             []OLAPReportMeasure `json:"measures"`
+	Report               string              `json:"report"`
+	Status               string              `json:"status"`
+	UpdatedAt            time.Time           `json:"updated_at,omitempty"`
+	VisualizationOptions interface{}         `json:"visualization_options"`
+}
+
+// OLAPDimension represents a dimension of an OLAP report with its members, in the order used by the report data.
+type OLAPDimension struct {
+	Name    string
+	Members []OLAPDimensionMember
+}
+
+// OLAPCell represents one data point of an OLAP report: a member of each dimension and a value for each measure.
+type OLAPCell struct {
+	Members []OLAPDimensionMember
+	Values  []float64
+}
+
+// UnmarshalJSON decodes a dimension from CloudHealth's `{"name": [members]}` format.
+func (d *OLAPDimension) UnmarshalJSON(data []byte) error {
+	var dimension map[string][]OLAPDimensionMember
+	err := json.Unmarshal(data, &dimension)
+	if err != nil {
+		return err
+	}
+
+	if len(dimension) != 1 {
+		return fmt.Errorf("expected a single dimension, got %d", len(dimension))
+	}
+
+	for name, members := range dimension {
+		d.Name = name
+		d.Members = members
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes a dimension into CloudHealth's `{"name": [members]}` format.
+func (d OLAPDimension) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string][]OLAPDimensionMember{d.Name: d.Members})
+}
+
+// Dimension returns the dimension of the report with the given name.
+func (r *OLAPReport) Dimension(name string) (*OLAPDimension, bool) {
+	for i := range r.Dimensions {
+		if r.Dimensions[i].Name == name {
+			return &r.Dimensions[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// dimensionMembers returns the members of the dimension with the given name, excluding the total.
+func (r *OLAPReport) dimensionMembers(name string) []OLAPDimensionMember {
+	dimension, ok := r.Dimension(name)
+	if !ok {
+		return nil
+	}
+
+	var members []OLAPDimensionMember
+	for _, member := range dimension.Members {
+		if member.Name != TotalMember {
+			members = append(members, member)
+		}
+	}
+
+	return members
+}
+
+// MeasureIndex returns the index of the measure with the given name in each cell's values, or -1.
+func (r *OLAPReport) MeasureIndex(name string) int {
+	for i, measure := range r.Measures {
+		if measure.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Cells decodes the nested data of the report into one cell per combination of dimension members.
+// Missing values are returned as 0.
+func (r *OLAPReport) Cells() ([]OLAPCell, error) {
+	if len(r.Data) == 0 {
+		return nil, nil
+	}
+
+	var data interface{}
+	err := json.Unmarshal(r.Data, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	var cells []OLAPCell
+	err = r.decodeCells(data, nil, &cells)
+	if err != nil {
+		return nil, err
+	}
+
+	return cells, nil
+}
+
+// decodeCells walks one level of the report data per dimension and appends the measures found at the leaves.
+func (r *OLAPReport) decodeCells(data interface{}, members []OLAPDimensionMember, cells *[]OLAPCell) error {
+	values, ok := data.([]interface{})
+	if !ok {
+		return fmt.Errorf("unexpected report data at depth %d", len(members))
+	}
+
+	// Leaves hold one value per measure
+	if len(members) == len(r.Dimensions) {
+		cell := OLAPCell{
+			Members: append([]OLAPDimensionMember(nil), members...),
+			Values:  make([]float64, len(values)),
+		}
+		for i, value := range values {
+			if number, ok := value.(float64); ok {
+				cell.Values[i] = number
+			}
+		}
+		*cells = append(*cells, cell)
+		return nil
+	}
+
+	dimension := r.Dimensions[len(members)]
+	if len(values) > len(dimension.Members) {
+		return fmt.Errorf("dimension `%s` has %d members but the report data has %d", dimension.Name, len(dimension.Members), len(values))
+	}
+
+	for i, value := range values {
+		err := r.decodeCells(value, append(members, dimension.Members[i]), cells)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Cells decodes the report data into one cell per AWS Service Category, in the same shape as OLAPReport.Cells.
+func (r *AWSCostHistoryReport) Cells() ([]OLAPCell, error) {
+	var categories []AWSCostHistoryReportAwsServiceCategory
+	for _, dimension := range r.Dimensions {
+		if len(dimension.AwsServiceCategory) > 0 {
+			categories = dimension.AwsServiceCategory
+			break
+		}
+	}
+
+	if len(r.Data) > len(categories) {
+		return nil, fmt.Errorf("dimension `%s` has %d members but the report data has %d", DimensionAWSServiceCategory, len(categories), len(r.Data))
+	}
+
+	cells := make([]OLAPCell, len(r.Data))
+	for i, values := range r.Data {
+		cells[i] = OLAPCell{
+			Members: []OLAPDimensionMember{categories[i]},
+			Values:  values,
+		}
+	}
+
+	return cells, nil
+}
+
+// MeasureIndex returns the index of the measure with the given name in each cell's values, or -1.
+func (r *AWSCostHistoryReport) MeasureIndex(name string) int {
+	for i, measure := range r.Measures {
+		if measure.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// encode returns the query string of the options, checking them locally first.
+func (o *OLAPReportRequestOptions) encode(requireTime bool) (string, error) {
+	params := url.Values{}
+
+	if len(o.Measures) == 0 {
+		return "", errors.New("the `measures` property is required and cannot be blank")
+	}
+	params["measures[]"] = o.Measures
+
+	if len(o.Dimensions) > 0 {
+		params["dimensions[]"] = o.Dimensions
+	}
+
+	if o.Interval != "" || requireTime {
+		err := o.Interval.Validate()
+		if err != nil {
+			return "", err
+		}
+		params.Set("interval", string(o.Interval))
+	}
+
+	if !o.TimeRange.IsZero() || requireTime {
+		timeFilter, err := o.TimeRange.Encode(o.Interval)
+		if err != nil {
+			return "", err
+		}
+		params.Add("filters[]", fmt.Sprintf("%s:select:%s", DimensionTime, timeFilter))
+	}
+
+	for _, filter := range o.Filters {
+		if filter.Dimension == "" || len(filter.Members) == 0 {
+			return "", errors.New("filters require a dimension and at least one member")
+		}
+		params.Add("filters[]", filter.String())
+	}
+
+	if o.ClientAPIID != "" {
+		params.Set("client_api_id", o.ClientAPIID)
+	}
+
+	return params.Encode(), nil
+}
+
+// withCostHistoryDefaults returns a copy of the options using the cost measure and the given
+// dimension when none are specified. Nil options are treated as zero options.
+func withCostHistoryDefaults(requestOptions *OLAPReportRequestOptions, dimension string) *OLAPReportRequestOptions {
+	var options OLAPReportRequestOptions
+	if requestOptions != nil {
+		options = *requestOptions
+	}
+
+	if len(options.Measures) == 0 {
+		options.Measures = []string{MeasureCost}
+	}
+
+	if len(options.Dimensions) == 0 {
+		options.Dimensions = []string{dimension}
+	}
+
+	return &options
+}
+
+// GetOLAPReport gets any OLAP report, such as OLAPReportCostHistory, with the given options.
+func (s *Client) GetOLAPReport(report string, requestOptions *OLAPReportRequestOptions) (*OLAPReport, error) {
+	return s.GetOLAPReportWithContext(context.Background(), report, requestOptions)
+}
+
+// GetOLAPReportWithContext gets any OLAP report with the given options, bound to the given context.
+func (s *Client) GetOLAPReportWithContext(ctx context.Context, report string, requestOptions *OLAPReportRequestOptions) (*OLAPReport, error) {
+	return s.getOLAPReport(ctx, report, requestOptions, true)
+}
+
+// getOLAPReport gets any OLAP report with the given options, through the report cache unless cached is false.
+func (s *Client) getOLAPReport(ctx context.Context, report string, requestOptions *OLAPReportRequestOptions, cached bool) (*OLAPReport, error) {
+	// History reports can't be requested without a time range
+	query, err := requestOptions.encode(strings.HasSuffix(report, "/history"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Set up the URL
+	relativeURL := fmt.Sprintf("olap_reports/%s?%s", report, query)
+
+	// Make the API call, unless the response is cached
+	fetch := func() ([]byte, error) {
+		return getReportResponse(ctx, s, relativeURL)
+	}
+	var responseBody []byte
+	if cached {
+		responseBody, err = getCachedReportResponse(s, relativeURL, requestOptions.TimeRange, requestOptions.Interval, fetch)
+	} else {
+		responseBody, err = fetch()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the response data into the OLAPReport struct
+	var olapReport OLAPReport
+	err = json.Unmarshal(responseBody, &olapReport)
+	if err != nil {
+		return nil, err
+	}
+
+	return &olapReport, nil
+}
@@ -0,0 +1,152 @@
+package cloudhealth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const defaultCurrentCostReport = `{
+	"report": "cost/current",
+	"status": "complete",
+	"dimensions": [
+		{"AWS-Account": [
+			{"name": "total", "label": "Total", "parent": -1},
+			{"name": "123", "label": "Production", "parent": 0}
+		]},
+		{"AWS-Service-Category": [
+			{"name": "ec2", "label": "EC2 - Compute", "parent": -1},
+			{"name": "s3", "label": "S3", "parent": -1}
+		]}
+	],
+	"measures": [
+		{"name": "cost", "label": "Cost ($)"},
+		{"name": "projected_cost", "label": "Projected Cost ($)"}
+	],
+	"data": [
+		[[150.5, 300.0], [20.0, null]],
+		[[100.0, 210.0], [5.0, 10.0]]
+	]
+}`
+
+func TestOLAPReportRequestOptionsEncode(t *testing.T) {
+	options := OLAPReportRequestOptions{
+		Interval:   IntervalMonthly,
+		TimeRange:  TimeIndices(-1),
+		Measures:   []string{MeasureCost},
+		Dimensions: []string{DimensionAWSServiceCategory},
+		Filters:    []OLAPFilter{{Dimension: DimensionAWSAccount, Reject: true, Members: []string{"1", "2"}}},
+	}
+
+	query, err := options.encode(true)
+	assert.NoError(t, err)
+	assert.Equal(t, "dimensions%5B%5D=AWS-Service-Category&filters%5B%5D=time%3Aselect%3A-1&filters%5B%5D=AWS-Account%3Areject%3A1%2C2&interval=monthly&measures%5B%5D=cost", query)
+
+	_, err = (&OLAPReportRequestOptions{Measures: []string{MeasureCost}}).encode(true)
+	assert.EqualError(t, err, "the `interval` property is required and cannot be blank")
+
+	_, err = (&OLAPReportRequestOptions{}).encode(false)
+	assert.EqualError(t, err, "the `measures` property is required and cannot be blank")
+}
+
+func TestGetCurrentCosts(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expectedURL := "/olap_reports/cost/current"
+		if r.URL.EscapedPath() != expectedURL {
+			t.Errorf("Expected request to ‘%s’, got ‘%s’", expectedURL, r.URL.EscapedPath())
+		}
+		assert.Equal(t, []string{MeasureCost, MeasureProjectedCost}, r.URL.Query()["measures[]"])
+		assert.Equal(t, []string{DimensionAWSAccount, DimensionAWSServiceCategory}, r.URL.Query()["dimensions[]"])
+		fmt.Fprint(w, defaultCurrentCostReport)
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+
+	currentCosts, err := c.GetCurrentCosts(&CurrentCostRequestOptions{Dimensions: []string{DimensionAWSAccount, DimensionAWSServiceCategory}})
+	assert.NoError(t, err)
+	assert.Len(t, currentCosts, 4)
+	assert.Equal(t, "total", currentCosts[0].Members[0].Name)
+	assert.Equal(t, "ec2", currentCosts[0].Members[1].Name)
+	assert.Equal(t, 150.5, currentCosts[0].MonthToDate)
+	assert.Equal(t, 300.0, currentCosts[0].Forecast)
+	assert.Equal(t, 0.0, currentCosts[1].Forecast)
+	assert.Equal(t, "Production", currentCosts[3].Members[0].Label)
+	assert.Equal(t, 10.0, currentCosts[3].Forecast)
+}
+
+func TestGetAzureCostHistoryReport(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expectedURL := "/olap_reports/azure_cost/history"
+		if r.URL.EscapedPath() != expectedURL {
+			t.Errorf("Expected request to ‘%s’, got ‘%s’", expectedURL, r.URL.EscapedPath())
+		}
+		assert.Equal(t, []string{MeasureCost}, r.URL.Query()["measures[]"])
+		assert.Equal(t, []string{DimensionAzureService}, r.URL.Query()["dimensions[]"])
+		assert.Equal(t, "monthly", r.URL.Query().Get("interval"))
+		fmt.Fprint(w, `{
+			"dimensions": [{"Azure-Service": [{"name": "total"}, {"name": "vm", "label": "Virtual Machines"}]}],
+			"measures": [{"name": "cost"}],
+			"data": [[12.0], [12.0]]
+		}`)
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+
+	report, err := c.GetAzureCostHistoryReport(&OLAPReportRequestOptions{Interval: IntervalMonthly, TimeRange: TimeIndices(-1)})
+	assert.NoError(t, err)
+	assert.Len(t, report.Services(), 1)
+	assert.Equal(t, "Virtual Machines", report.Services()[0].Label)
+	assert.Empty(t, report.Subscriptions())
+
+	cells, err := report.Cells()
+	assert.NoError(t, err)
+	assert.Len(t, cells, 2)
+}
+
+func TestGetGCPCostHistoryReportWithContext(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expectedURL := "/olap_reports/gcp_cost/history"
+		if r.URL.EscapedPath() != expectedURL {
+			t.Errorf("Expected request to ‘%s’, got ‘%s’", expectedURL, r.URL.EscapedPath())
+		}
+		assert.Equal(t, []string{DimensionGCPService}, r.URL.Query()["dimensions[]"])
+		fmt.Fprint(w, `{
+			"dimensions": [{"GCP-Service": [{"name": "total"}, {"name": "compute", "label": "Compute Engine"}]}],
+			"measures": [{"name": "cost"}],
+			"data": [[7.0], [7.0]]
+		}`)
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+
+	report, err := c.GetGCPCostHistoryReportWithContext(context.Background(), &OLAPReportRequestOptions{Interval: IntervalMonthly, TimeRange: TimeIndices(-1)})
+	assert.NoError(t, err)
+	assert.Len(t, report.Services(), 1)
+	assert.Equal(t, "Compute Engine", report.Services()[0].Label)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = c.GetGCPCostHistoryReportWithContext(ctx, &OLAPReportRequestOptions{Interval: IntervalMonthly, TimeRange: TimeIndices(-2)})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestGetCostHistoryReportNilOptions(t *testing.T) {
+	c, err := NewClient("apiKey", "http://127.0.0.1:1/")
+	assert.NoError(t, err)
+
+	_, err = c.GetAzureCostHistoryReport(nil)
+	assert.EqualError(t, err, "the `interval` property is required and cannot be blank")
+
+	_, err = c.GetGCPCostHistoryReportWithContext(context.Background(), nil)
+	assert.EqualError(t, err, "the `interval` property is required and cannot be blank")
+}
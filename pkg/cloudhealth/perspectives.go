@@ -0,0 +1,186 @@
+package cloudhealth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrPerspectiveNotFound is returned when no Perspective matches the requested name.
+var ErrPerspectiveNotFound = errors.New("perspective not found")
+
+// ErrPerspectiveGroupNotFound is returned when a Perspective has no group with the requested name.
+var ErrPerspectiveGroupNotFound = errors.New("perspective group not found")
+
+// PerspectiveSummary represents a Perspective as listed in CloudHealth.
+type PerspectiveSummary struct {
+	ID     string `json:"-"`
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+}
+
+// Perspective represents the schema of a Perspective in CloudHealth.
+type Perspective struct {
+	ID     string            `json:"-"`
+	Schema PerspectiveSchema `json:"schema"`
+}
+
+// PerspectiveSchema represents the rules and groups of a Perspective.
+type PerspectiveSchema struct {
+	Name             string                   `json:"name"`
+	IncludeInReports string                   `json:"include_in_reports"`
+	Rules            []map[string]interface{} `json:"rules"`
+	Merges           []map[string]interface{} `json:"merges"`
+	Constants        []PerspectiveConstant    `json:"constants"`
+}
+
+// PerspectiveConstant represents a set of groups of a Perspective, such as its static groups.
+type PerspectiveConstant struct {
+	Type string             `json:"type"`
+	List []PerspectiveGroup `json:"list"`
+}
+
+// PerspectiveGroup represents a group of a Perspective.
+type PerspectiveGroup struct {
+	RefID   string `json:"ref_id"`
+	BlockID string `json:"blk_id,omitempty"`
+	Name    string `json:"name"`
+	Value   string `json:"val,omitempty"`
+	IsOther string `json:"is_other,omitempty"`
+}
+
+// GetPerspectives gets all Perspectives, sorted by name.
+func (s *Client) GetPerspectives() ([]PerspectiveSummary, error) {
+	// Set up the URL
+	relativeURL := fmt.Sprintf("v1/perspective_schemas")
+
+	// Make the API call
+	responseBody, err := getResponsePage(s, relativeURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the response data, which is keyed by Perspective ID
+	var perspectivesByID map[string]PerspectiveSummary
+	err = json.Unmarshal(responseBody, &perspectivesByID)
+	if err != nil {
+		return nil, err
+	}
+
+	perspectives := make([]PerspectiveSummary, 0, len(perspectivesByID))
+	for id, perspective := range perspectivesByID {
+		perspective.ID = id
+		perspectives = append(perspectives, perspective)
+	}
+	sort.Slice(perspectives, func(i, j int) bool {
+		return perspectives[i].Name < perspectives[j].Name
+	})
+
+	return perspectives, nil
+}
+
+// GetSinglePerspective gets the Perspective with the specified CloudHealth ID.
+func (s *Client) GetSinglePerspective(id string) (*Perspective, error) {
+	// Set up the URL
+	relativeURL := fmt.Sprintf("v1/perspective_schemas/%s", id)
+
+	// Make the API call
+	responseBody, err := getResponsePage(s, relativeURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the response data into the Perspective struct
+	var perspective Perspective
+	err = json.Unmarshal(responseBody, &perspective)
+	if err != nil {
+		return nil, err
+	}
+	perspective.ID = id
+
+	return &perspective, nil
+}
+
+// GetPerspectiveByName gets the Perspective with the specified name, ignoring case.
+func (s *Client) GetPerspectiveByName(name string) (*Perspective, error) {
+	perspectives, err := s.GetPerspectives()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, perspective := range perspectives {
+		if strings.EqualFold(perspective.Name, name) {
+			return s.GetSinglePerspective(perspective.ID)
+		}
+	}
+
+	return nil, ErrPerspectiveNotFound
+}
+
+// Dimension returns the name of the OLAP report dimension grouping costs by this Perspective.
+func (p *Perspective) Dimension() string {
+	return p.ID
+}
+
+// Groups returns all named groups of the Perspective.
+func (p *Perspective) Groups() []PerspectiveGroup {
+	var groups []PerspectiveGroup
+	for _, constant := range p.Schema.Constants {
+		for _, group := range constant.List {
+			if group.Name != "" {
+				groups = append(groups, group)
+			}
+		}
+	}
+
+	return groups
+}
+
+// GroupID returns the ID of the group with the specified name, ignoring case.
+func (p *Perspective) GroupID(name string) (string, error) {
+	for _, group := range p.Groups() {
+		if strings.EqualFold(group.Name, name) {
+			return group.RefID, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: `%s` in perspective `%s`", ErrPerspectiveGroupNotFound, name, p.Schema.Name)
+}
+
+// Filter returns an OLAP report filter selecting the groups with the specified names.
+func (p *Perspective) Filter(groupNames ...string) (OLAPFilter, error) {
+	filter := OLAPFilter{Dimension: p.Dimension()}
+
+	for _, name := range groupNames {
+		id, err := p.GroupID(name)
+		if err != nil {
+			return OLAPFilter{}, err
+		}
+		filter.Members = append(filter.Members, id)
+	}
+
+	return filter, nil
+}
+
+// PerspectiveFilter returns an OLAP report filter from an expression in business terms,
+// such as "Team: Payments" or "Team: Payments, Checkout", resolving the Perspective and group names.
+func (s *Client) PerspectiveFilter(expression string) (OLAPFilter, error) {
+	parts := strings.SplitN(expression, ":", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+		return OLAPFilter{}, fmt.Errorf("invalid perspective expression `%s`, expected `Perspective: Group[, Group...]`", expression)
+	}
+
+	perspective, err := s.GetPerspectiveByName(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return OLAPFilter{}, err
+	}
+
+	var groupNames []string
+	for _, name := range strings.Split(parts[1], ",") {
+		groupNames = append(groupNames, strings.TrimSpace(name))
+	}
+
+	return perspective.Filter(groupNames...)
+}
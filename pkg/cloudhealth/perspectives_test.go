@@ -0,0 +1,124 @@
+package cloudhealth
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const perspectiveSchemasResponse = `{
+	"1234567890": {"name": "Team", "active": true},
+	"1234567891": {"name": "Environment", "active": false}
+}`
+
+const perspectiveSchemaResponse = `{
+	"schema": {
+		"name": "Team",
+		"include_in_reports": "true",
+		"rules": [],
+		"merges": [],
+		"constants": [
+			{"type": "Static Group", "list": [
+				{"ref_id": "1234567890001", "name": "Payments"},
+				{"ref_id": "1234567890002", "name": "Checkout & Billing"},
+				{"ref_id": "1234567890003", "is_other": "true"}
+			]}
+		]
+	}
+}`
+
+func newPerspectivesServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+
+		switch r.URL.EscapedPath() {
+		case "/v1/perspective_schemas":
+			w.Write([]byte(perspectiveSchemasResponse))
+		case "/v1/perspective_schemas/1234567890":
+			w.Write([]byte(perspectiveSchemaResponse))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestGetPerspectives(t *testing.T) {
+	ts := newPerspectivesServer(t)
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+
+	perspectives, err := c.GetPerspectives()
+	assert.NoError(t, err)
+	assert.Equal(t, []PerspectiveSummary{
+		{ID: "1234567891", Name: "Environment", Active: false},
+		{ID: "1234567890", Name: "Team", Active: true},
+	}, perspectives)
+}
+
+func TestGetSinglePerspective(t *testing.T) {
+	ts := newPerspectivesServer(t)
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+
+	perspective, err := c.GetSinglePerspective("1234567890")
+	assert.NoError(t, err)
+	assert.Equal(t, "1234567890", perspective.Dimension())
+	assert.Equal(t, "Team", perspective.Schema.Name)
+	assert.Len(t, perspective.Groups(), 2)
+
+	id, err := perspective.GroupID("payments")
+	assert.NoError(t, err)
+	assert.Equal(t, "1234567890001", id)
+
+	_, err = perspective.GroupID("Marketing")
+	assert.ErrorIs(t, err, ErrPerspectiveGroupNotFound)
+
+	_, err = c.GetSinglePerspective("404")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestPerspectiveFilter(t *testing.T) {
+	ts := newPerspectivesServer(t)
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+
+	filter, err := c.PerspectiveFilter("team: Payments, Checkout & Billing")
+	assert.NoError(t, err)
+	assert.Equal(t, OLAPFilter{Dimension: "1234567890", Members: []string{"1234567890001", "1234567890002"}}, filter)
+
+	_, err = c.PerspectiveFilter("Cost Center: Payments")
+	assert.ErrorIs(t, err, ErrPerspectiveNotFound)
+
+	_, err = c.PerspectiveFilter("Team")
+	assert.Error(t, err)
+}
+
+func TestGetAWSCostHistoryReportEscapesFilters(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.URL.Query()["filters[]"], "Team:select:a&b,c")
+		assert.Empty(t, r.URL.Query()["b,c"])
+
+		w.Write([]byte(`{"report": "cost_history", "dimensions": [], "measures": [], "data": []}`))
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+
+	_, err = c.GetAWSCostHistoryReport(&AWSCostHistoryRequestOptions{
+		Measures:  MeasureCost,
+		Interval:  IntervalMonthly,
+		TimeRange: TimeIndices(-1),
+		Filters:   []OLAPFilter{{Dimension: "Team", Members: []string{"a&b", "c"}}},
+	})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,267 @@
+package cloudhealth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Operators combining the clauses of a PolicyCondition.
+const (
+	PolicyConditionAll = "and"
+	PolicyConditionAny = "or"
+)
+
+// Policies represents all Policies defined in CloudHealth.
+type Policies struct {
+	Policies []Policy `json:"policies"`
+}
+
+// Policy represents a CloudHealth Policy: blocks of conditions evaluated on assets, with the actions to take on violations.
+// CreatedAt and UpdatedAt are set by CloudHealth, and left out of requests when nil.
+type Policy struct {
+	ID          int           `json:"id,omitempty"`
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Status      string        `json:"status,omitempty"`
+	Blocks      []PolicyBlock `json:"blocks,omitempty"`
+	CreatedAt   *time.Time    `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time    `json:"updated_at,omitempty"`
+}
+
+// PolicyBlocks represents the blocks of a Policy.
+type PolicyBlocks struct {
+	PolicyBlocks []PolicyBlock `json:"policy_blocks"`
+}
+
+// PolicyBlock represents a condition on assets of an asset type and the actions taken when it is met.
+type PolicyBlock struct {
+	ID        int             `json:"id,omitempty"`
+	Name      string          `json:"name"`
+	AssetType string          `json:"asset_type"`
+	Severity  string          `json:"severity,omitempty"`
+	Condition PolicyCondition `json:"condition"`
+	Actions   []PolicyAction  `json:"actions,omitempty"`
+}
+
+// PolicyCondition represents clauses combined with PolicyConditionAll or PolicyConditionAny.
+type PolicyCondition struct {
+	Operator string         `json:"operator"`
+	Clauses  []PolicyClause `json:"clauses"`
+}
+
+// PolicyClause represents the comparison of an asset field to a value, such as `instance_type = m5.large`.
+type PolicyClause struct {
+	Field    string      `json:"field"`
+	Operator string      `json:"op"`
+	Value    interface{} `json:"val"`
+}
+
+// PolicyAction represents an action taken on a violation, such as a notification, with its parameters.
+type PolicyAction struct {
+	Type       string            `json:"type"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// PolicyViolations represents the violations of a Policy.
+type PolicyViolations struct {
+	Violations []PolicyViolation `json:"violations"`
+}
+
+// PolicyViolation represents a violation of a block of a Policy, with the assets affected.
+type PolicyViolation struct {
+	ID             int                    `json:"id"`
+	PolicyID       int                    `json:"policy_id"`
+	BlockID        int                    `json:"block_id"`
+	AssetType      string                 `json:"asset_type"`
+	Severity       string                 `json:"severity,omitempty"`
+	DetectedAt     Timestamp              `json:"detected_at"`
+	AffectedAssets []PolicyViolationAsset `json:"affected_assets"`
+}
+
+// PolicyViolationAsset represents an asset affected by a violation.
+type PolicyViolationAsset struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	AccountID string `json:"account_id,omitempty"`
+}
+
+// GetSinglePolicy gets the Policy with the specified CloudHealth Policy ID.
+func (s *Client) GetSinglePolicy(id int) (*Policy, error) {
+	// Set up the URL
+	relativeURL := fmt.Sprintf("v1/policies/%d", id)
+
+	// Make the API call
+	responseBody, err := getResponsePage(s, relativeURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the response data into the Policy struct
+	var policy Policy
+	err = json.Unmarshal(responseBody, &policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+// GetPolicies gets all Policies defined in CloudHealth.
+func (s *Client) GetPolicies() (*Policies, error) {
+	// Set variables we will need along the way
+	var policies Policies
+	var page, pageSize int = 1, 100
+
+	// Loop for paging
+	for {
+		// Set up the query parameters for the API
+		params := url.Values{"page": {strconv.Itoa(page)}, "per_page": {strconv.Itoa(pageSize)}}
+
+		// Set up the URL
+		relativeURL := fmt.Sprintf("v1/policies?%s", params.Encode())
+
+		// Make the API call
+		responseBody, err := getResponsePage(s, relativeURL)
+		if err != nil {
+			return nil, err
+		}
+
+		// Unmarshal the response data into the page of Policies
+		var pagePolicies Policies
+		err = json.Unmarshal(responseBody, &pagePolicies)
+		if err != nil {
+			return nil, err
+		}
+		policies.Policies = append(policies.Policies, pagePolicies.Policies...)
+
+		// Check length of the page to determine if we should break out of the loop
+		if len(pagePolicies.Policies) < pageSize {
+			break
+		}
+
+		// Increment page counter
+		page++
+	}
+
+	return &policies, nil
+}
+
+// GetPolicyBlocks gets the blocks of the Policy with the specified CloudHealth Policy ID.
+func (s *Client) GetPolicyBlocks(policyID int) (*PolicyBlocks, error) {
+	// Set up the URL
+	relativeURL := fmt.Sprintf("v1/policies/%d/policy_blocks", policyID)
+
+	// Make the API call
+	responseBody, err := getResponsePage(s, relativeURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the response data into the PolicyBlocks struct
+	var blocks PolicyBlocks
+	err = json.Unmarshal(responseBody, &blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	return &blocks, nil
+}
+
+// GetPolicyViolations gets the violations of the Policy with the specified CloudHealth Policy ID.
+func (s *Client) GetPolicyViolations(policyID int) (*PolicyViolations, error) {
+	// Set variables we will need along the way
+	var violations PolicyViolations
+	var page, pageSize int = 1, 100
+
+	// Loop for paging
+	for {
+		// Set up the query parameters for the API
+		params := url.Values{"page": {strconv.Itoa(page)}, "per_page": {strconv.Itoa(pageSize)}}
+
+		// Set up the URL
+		relativeURL := fmt.Sprintf("v1/policies/%d/violations?%s", policyID, params.Encode())
+
+		// Make the API call
+		responseBody, err := getResponsePage(s, relativeURL)
+		if err != nil {
+			return nil, err
+		}
+
+		// Unmarshal the response data into the page of PolicyViolations
+		var pageViolations PolicyViolations
+		err = json.Unmarshal(responseBody, &pageViolations)
+		if err != nil {
+			return nil, err
+		}
+		violations.Violations = append(violations.Violations, pageViolations.Violations...)
+
+		// Check length of the page to determine if we should break out of the loop
+		if len(pageViolations.Violations) < pageSize {
+			break
+		}
+
+		// Increment page counter
+		page++
+	}
+
+	return &violations, nil
+}
+
+// CreatePolicy creates a new Policy in CloudHealth.
+func (s *Client) CreatePolicy(policy Policy) (*Policy, error) {
+	// Set up the URL
+	relativeURL := "v1/policies"
+
+	// Make the API call
+	responseBody, err := createResource(s, relativeURL, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the response data into the Policy struct
+	var returnedPolicy Policy
+	err = json.Unmarshal(responseBody, &returnedPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &returnedPolicy, nil
+}
+
+// UpdatePolicy updates an existing Policy in CloudHealth.
+func (s *Client) UpdatePolicy(policy Policy) (*Policy, error) {
+	// Set up the URL
+	relativeURL := fmt.Sprintf("v1/policies/%d", policy.ID)
+
+	// Make the API call
+	responseBody, err := updateResource(s, relativeURL, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the response data into the Policy struct
+	var returnedPolicy Policy
+	err = json.Unmarshal(responseBody, &returnedPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &returnedPolicy, nil
+}
+
+// DeletePolicy removes the Policy with the specified CloudHealth ID.
+func (s *Client) DeletePolicy(id int) error {
+	// Set up the URL
+	relativeURL := fmt.Sprintf("v1/policies/%d", id)
+
+	// Make the API call
+	_, err := deleteResource(s, relativeURL)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package cloudhealth
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var defaultPolicy = Policy{
+	ID:   42,
+	Name: "Untagged instances",
+	Blocks: []PolicyBlock{
+		{
+			Name:      "Missing owner",
+			AssetType: "AwsInstance",
+			Condition: PolicyCondition{
+				Operator: PolicyConditionAll,
+				Clauses:  []PolicyClause{{Field: "is_active", Operator: "=", Value: true}, {Field: "tags.owner", Operator: "Is Null"}},
+			},
+			Actions: []PolicyAction{{Type: "notify", Parameters: map[string]string{"email": "finops@example.com"}}},
+		},
+	},
+}
+
+func TestCreatePolicy(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/v1/policies", r.URL.EscapedPath())
+
+		body, _ := ioutil.ReadAll(r.Body)
+		var policy Policy
+		assert.NoError(t, json.Unmarshal(body, &policy))
+		assert.Equal(t, defaultPolicy.Blocks, policy.Blocks)
+		assert.NotContains(t, string(body), "created_at")
+		assert.NotContains(t, string(body), "updated_at")
+
+		createdAt := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+		policy.CreatedAt, policy.UpdatedAt = &createdAt, &createdAt
+		body, _ = json.Marshal(policy)
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+
+	policy, err := c.CreatePolicy(defaultPolicy)
+	assert.NoError(t, err)
+	assert.Equal(t, "Untagged instances", policy.Name)
+	if assert.NotNil(t, policy.CreatedAt) {
+		assert.Equal(t, 2022, policy.CreatedAt.Year())
+	}
+}
+
+func TestGetPolicyViolations(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/v1/policies/42/violations", r.URL.EscapedPath())
+
+		w.Write([]byte(`{"violations": [{"id": 1, "policy_id": 42, "block_id": 7, "asset_type": "AwsInstance", "detected_at": "2022-03-01 12:00:00 UTC", "affected_assets": [{"id": 123, "name": "web-1", "account_id": "123456789012"}]}, {"id": 2, "policy_id": 42, "detected_at": null}]}`))
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+
+	violations, err := c.GetPolicyViolations(42)
+	assert.NoError(t, err)
+	if assert.Len(t, violations.Violations, 2) {
+		assert.Equal(t, 7, violations.Violations[0].BlockID)
+		assert.Equal(t, time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC), violations.Violations[0].DetectedAt.UTC())
+		assert.Equal(t, []PolicyViolationAsset{{ID: 123, Name: "web-1", AccountID: "123456789012"}}, violations.Violations[0].AffectedAssets)
+		assert.True(t, violations.Violations[1].DetectedAt.IsZero())
+	}
+}
@@ -0,0 +1,241 @@
+package cloudhealth
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Defaults of the renewal planner. DefaultRenewalDiscountRate is the discount of commitments over on-demand
+// prices assumed when neither an on-demand price nor a discount rate is given, about that of one-year
+// commitments without upfront payment.
+const (
+	DefaultRenewalHorizonDays   = 90
+	DefaultRenewalDiscountRate  = 0.3
+	DefaultRenewalHistoryMonths = 3
+)
+
+// hoursPerMonth is the average number of hours in a month.
+const hoursPerMonth = 730
+
+// RenewalPlanOptions configures the renewal planner. Zero values use the defaults.
+type RenewalPlanOptions struct {
+	// HorizonDays selects commitments expiring within that many days.
+	HorizonDays int
+	// OnDemandHourlyPrices is the on-demand hourly price of one unit of each commitment type, such as
+	// an instance type, used to price the usage a commitment covers at on-demand rates.
+	OnDemandHourlyPrices map[string]float64
+	// DiscountRate is the discount of commitments over on-demand prices, between 0 and 1, used instead to
+	// estimate the on-demand price of commitments of types without a price. DiscountRates overrides it per kind of commitment.
+	DiscountRate  float64
+	DiscountRates map[CommitmentKind]float64
+	// Now is the time expiries are measured from, the current time by default.
+	Now time.Time
+}
+
+// RenewalCandidate represents a commitment expiring within the horizon and the cost of not renewing it.
+// Monthly amounts are averages over a month of 730 hours. MonthlyOnDemandCost is the cost of running the usage
+// the commitment covers at on-demand prices; OnDemandEstimated is set when it is estimated from a discount rate
+// rather than an on-demand price. MonthlyExposure is MonthlyOnDemandCost minus MonthlyCommitmentCost: the monthly
+// increase in cost if the commitment lapses, negative when it is used so little that renewing it costs more than
+// paying on demand. AccountMonthlyCost is the average monthly cost of the account over the cost history, and
+// ExposureShare the MonthlyExposure relative to it, or 0 when unknown; the cost history plays no part in
+// MonthlyOnDemandCost or MonthlyExposure.
+type RenewalCandidate struct {
+	Commitment            Commitment `json:"commitment"`
+	DaysUntilExpiry       int        `json:"days_until_expiry"`
+	MonthlyCommitmentCost float64    `json:"monthly_commitment_cost"`
+	MonthlyOnDemandCost   float64    `json:"monthly_on_demand_cost"`
+	OnDemandEstimated     bool       `json:"on_demand_estimated"`
+	MonthlyExposure       float64    `json:"monthly_exposure"`
+	AccountMonthlyCost    float64    `json:"account_monthly_cost"`
+	ExposureShare         float64    `json:"exposure_share"`
+}
+
+// LoadCommitments reads commitments from a local JSON export, an array of Commitment.
+func LoadCommitments(r io.Reader) ([]Commitment, error) {
+	var commitments []Commitment
+	err := json.NewDecoder(r).Decode(&commitments)
+	if err != nil {
+		return nil, err
+	}
+
+	return commitments, nil
+}
+
+// accountMonthlyCosts returns the average monthly cost of each account of the cost records, keyed by account ID and by account name.
+func accountMonthlyCosts(history []CostRecord) (map[string]float64, map[string]float64) {
+	average := func(key func(record CostRecord) string) map[string]float64 {
+		totals := make(map[string]float64)
+		periods := make(map[string]map[string]bool)
+
+		for _, record := range history {
+			account := key(record)
+			if account == "" {
+				continue
+			}
+			totals[account] += record.Amount
+			if periods[account] == nil {
+				periods[account] = make(map[string]bool)
+			}
+			periods[account][record.Period] = true
+		}
+
+		averages := make(map[string]float64, len(totals))
+		for account, total := range totals {
+			averages[account] = total / float64(len(periods[account]))
+		}
+		return averages
+	}
+
+	byID := average(func(record CostRecord) string { return record.AccountID })
+	byName := average(func(record CostRecord) string { return record.AccountName })
+
+	return byID, byName
+}
+
+// PlanRenewals selects the commitments expiring within the horizon, already expired ones excluded, and
+// ranks them by MonthlyExposure, the on-demand cost of the usage they cover less the cost of the commitment,
+// largest first, then by expiry. Underused commitments have a negative exposure and rank last. Covered usage
+// is the committed capacity times its utilization, priced with the on-demand price of the commitment type
+// when known, or estimated from the discount rate otherwise.
+// The cost history, such as records of a monthly cost history report by account, is only used for the
+// AccountMonthlyCost and ExposureShare of each candidate, never to price covered usage.
+func PlanRenewals(commitments []Commitment, history []CostRecord, options *RenewalPlanOptions) []RenewalCandidate {
+	if options == nil {
+		options = &RenewalPlanOptions{}
+	}
+	horizon := options.HorizonDays
+	if horizon <= 0 {
+		horizon = DefaultRenewalHorizonDays
+	}
+	now := options.Now
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+
+	costsByID, costsByName := accountMonthlyCosts(history)
+
+	var candidates []RenewalCandidate
+	for _, commitment := range commitments {
+		days := commitment.DaysUntilExpiry(now)
+		if commitment.End.Before(now) || days > horizon {
+			continue
+		}
+
+		discount, ok := options.DiscountRates[commitment.Kind]
+		if !ok {
+			discount = options.DiscountRate
+		}
+		if discount <= 0 || discount >= 1 {
+			discount = DefaultRenewalDiscountRate
+		}
+
+		monthlyCommitment := commitment.HourlyCommitment * hoursPerMonth
+		monthlyCovered := monthlyCommitment * commitment.Utilization / 100
+		candidate := RenewalCandidate{
+			Commitment:            commitment,
+			DaysUntilExpiry:       days,
+			MonthlyCommitmentCost: monthlyCommitment,
+			MonthlyOnDemandCost:   monthlyCovered / (1 - discount),
+			OnDemandEstimated:     true,
+		}
+		if price, ok := options.OnDemandHourlyPrices[commitment.Type]; ok && price > 0 && commitment.Count > 0 {
+			candidate.MonthlyOnDemandCost = price * float64(commitment.Count) * hoursPerMonth * commitment.Utilization / 100
+			candidate.OnDemandEstimated = false
+		}
+		candidate.MonthlyExposure = candidate.MonthlyOnDemandCost - monthlyCommitment
+
+		accountCost, ok := costsByID[commitment.AccountID]
+		if !ok {
+			accountCost = costsByName[commitment.AccountName]
+		}
+		candidate.AccountMonthlyCost = accountCost
+		if accountCost > 0 {
+			candidate.ExposureShare = candidate.MonthlyExposure / accountCost
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].MonthlyExposure != candidates[j].MonthlyExposure {
+			return candidates[i].MonthlyExposure > candidates[j].MonthlyExposure
+		}
+		return candidates[i].Commitment.End.Before(candidates[j].Commitment.End)
+	})
+
+	return candidates
+}
+
+// PlanRenewals gets the active commitments and the monthly cost history of the last DefaultRenewalHistoryMonths
+// months by AWS account, and ranks the commitments expiring within the horizon as PlanRenewals does.
+func (s *Client) PlanRenewals(ctx context.Context, options *RenewalPlanOptions) ([]RenewalCandidate, error) {
+	commitments, err := s.GetCommitments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.GetOLAPReportWithContext(ctx, OLAPReportCostHistory, &OLAPReportRequestOptions{
+		Interval:   IntervalMonthly,
+		TimeRange:  LastMonths(DefaultRenewalHistoryMonths),
+		Measures:   []string{MeasureCost},
+		Dimensions: []string{DimensionTime, DimensionAWSAccount},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := NormalizeOLAPReport(ProviderAWS, report, MeasureCost, DefaultCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	return PlanRenewals(commitments, history, options), nil
+}
+
+// WriteRenewalPlanCSV writes one CSV row per renewal candidate, in rank order.
+func WriteRenewalPlanCSV(w io.Writer, candidates []RenewalCandidate) error {
+	writer := csv.NewWriter(w)
+
+	err := writer.Write([]string{
+		"rank", "kind", "id", "account_id", "account_name", "type", "region", "end", "days_until_expiry",
+		"monthly_commitment_cost", "monthly_on_demand_cost", "monthly_exposure", "exposure_share",
+	})
+	if err != nil {
+		return err
+	}
+
+	formatAmount := func(amount float64) string {
+		return strconv.FormatFloat(amount, 'f', 2, 64)
+	}
+
+	for i, candidate := range candidates {
+		commitment := candidate.Commitment
+		err = writer.Write([]string{
+			strconv.Itoa(i + 1),
+			string(commitment.Kind),
+			commitment.ID,
+			commitment.AccountID,
+			commitment.AccountName,
+			commitment.Type,
+			commitment.Region,
+			commitment.End.UTC().Format("2006-01-02"),
+			strconv.Itoa(candidate.DaysUntilExpiry),
+			formatAmount(candidate.MonthlyCommitmentCost),
+			formatAmount(candidate.MonthlyOnDemandCost),
+			formatAmount(candidate.MonthlyExposure),
+			strconv.FormatFloat(candidate.ExposureShare, 'f', 4, 64),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
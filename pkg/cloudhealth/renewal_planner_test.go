@@ -0,0 +1,156 @@
+package cloudhealth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var renewalPlanNow = time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+
+var testCommitments = []Commitment{
+	{Kind: CommitmentReservedInstance, ID: "r-1", AccountID: "111", Type: "m5.large", End: renewalPlanNow.AddDate(0, 0, 10), HourlyCommitment: 1, Utilization: 100},
+	{Kind: CommitmentSavingsPlan, ID: "sp-1", AccountID: "222", Type: "Compute", End: renewalPlanNow.AddDate(0, 0, 60), HourlyCommitment: 2, Utilization: 50},
+	{Kind: CommitmentReservedInstance, ID: "r-2", AccountID: "111", End: renewalPlanNow.AddDate(0, 0, 200), HourlyCommitment: 5, Utilization: 100},
+	{Kind: CommitmentReservedInstance, ID: "r-3", AccountID: "111", End: renewalPlanNow.AddDate(0, 0, -1), HourlyCommitment: 5, Utilization: 100},
+}
+
+func TestPlanRenewals(t *testing.T) {
+	history := []CostRecord{
+		{AccountID: "111", Period: "2022-01", Amount: 800},
+		{AccountID: "111", Period: "2022-01", Amount: 200},
+		{AccountID: "111", Period: "2022-02", Amount: 2000},
+	}
+
+	candidates := PlanRenewals(testCommitments, history, &RenewalPlanOptions{
+		Now:           renewalPlanNow,
+		DiscountRates: map[CommitmentKind]float64{CommitmentSavingsPlan: 0.5},
+	})
+
+	if assert.Len(t, candidates, 2) {
+		assert.Equal(t, "r-1", candidates[0].Commitment.ID)
+		assert.InDelta(t, 312.857, candidates[0].MonthlyExposure, 0.001)
+		assert.InDelta(t, 1500, candidates[0].AccountMonthlyCost, 0.001)
+		assert.InDelta(t, 0.2086, candidates[0].ExposureShare, 0.0001)
+
+		// Half used at half the on-demand price, the Savings Plan costs as much as the usage it covers
+		assert.Equal(t, "sp-1", candidates[1].Commitment.ID)
+		assert.Equal(t, 60, candidates[1].DaysUntilExpiry)
+		assert.InDelta(t, 1460, candidates[1].MonthlyCommitmentCost, 0.001)
+		assert.InDelta(t, 1460, candidates[1].MonthlyOnDemandCost, 0.001)
+		assert.InDelta(t, 0, candidates[1].MonthlyExposure, 0.001)
+		assert.Equal(t, 0.0, candidates[1].ExposureShare)
+	}
+
+	assert.Len(t, PlanRenewals(testCommitments, nil, &RenewalPlanOptions{Now: renewalPlanNow, HorizonDays: 30}), 1)
+
+	var buffer bytes.Buffer
+	assert.NoError(t, WriteRenewalPlanCSV(&buffer, candidates))
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if assert.Len(t, lines, 3) {
+		assert.Equal(t, "1,reserved_instance,r-1,111,,m5.large,,2022-03-11,10,730.00,1042.86,312.86,0.2086", lines[1])
+		assert.Equal(t, "2,savings_plan,sp-1,222,,Compute,,2022-04-30,60,1460.00,1460.00,0.00,0.0000", lines[2])
+	}
+}
+
+func TestPlanRenewalsOnDemandPrices(t *testing.T) {
+	commitments := []Commitment{
+		{Kind: CommitmentReservedInstance, ID: "r-1", AccountID: "111", AccountName: "222", Type: "m5.large", Count: 2, End: renewalPlanNow.AddDate(0, 0, 10), HourlyCommitment: 0.12, Utilization: 50},
+	}
+	history := []CostRecord{
+		{AccountID: "111", AccountName: "Production", Period: "2022-01", Amount: 1000},
+		{AccountID: "222", AccountName: "Staging", Period: "2022-01", Amount: 50},
+	}
+
+	candidates := PlanRenewals(commitments, history, &RenewalPlanOptions{
+		Now:                  renewalPlanNow,
+		OnDemandHourlyPrices: map[string]float64{"m5.large": 0.096},
+	})
+	if assert.Len(t, candidates, 1) {
+		// 2 instances × 0.096 × 730 hours × 50%, minus the 87.6 committed
+		assert.False(t, candidates[0].OnDemandEstimated)
+		assert.InDelta(t, 70.08, candidates[0].MonthlyOnDemandCost, 0.001)
+		assert.InDelta(t, -17.52, candidates[0].MonthlyExposure, 0.001)
+
+		// Account IDs and names are never mixed up
+		assert.InDelta(t, 1000, candidates[0].AccountMonthlyCost, 0.001)
+	}
+
+	candidates = PlanRenewals(commitments, history[1:], &RenewalPlanOptions{Now: renewalPlanNow})
+	if assert.Len(t, candidates, 1) {
+		assert.True(t, candidates[0].OnDemandEstimated)
+		assert.Equal(t, 0.0, candidates[0].AccountMonthlyCost)
+	}
+}
+
+func TestPlanRenewalsUnderusedCommitmentRanksLast(t *testing.T) {
+	commitments := []Commitment{
+		{Kind: CommitmentReservedInstance, ID: "r-low", AccountID: "111", End: renewalPlanNow.AddDate(0, 0, 5), HourlyCommitment: 1, Utilization: 20},
+		{Kind: CommitmentReservedInstance, ID: "r-high", AccountID: "111", End: renewalPlanNow.AddDate(0, 0, 30), HourlyCommitment: 0.5, Utilization: 100},
+	}
+
+	candidates := PlanRenewals(commitments, nil, &RenewalPlanOptions{Now: renewalPlanNow})
+	if assert.Len(t, candidates, 2) {
+		assert.Equal(t, "r-high", candidates[0].Commitment.ID)
+		assert.InDelta(t, 156.429, candidates[0].MonthlyExposure, 0.001)
+
+		// 20% of 730 at a 30% discount is worth less on demand than the 730 committed
+		assert.Equal(t, "r-low", candidates[1].Commitment.ID)
+		assert.InDelta(t, 208.571, candidates[1].MonthlyOnDemandCost, 0.001)
+		assert.InDelta(t, -521.429, candidates[1].MonthlyExposure, 0.001)
+	}
+}
+
+func TestLoadCommitments(t *testing.T) {
+	commitments, err := LoadCommitments(strings.NewReader(`[{"kind": "savings_plan", "id": "sp-1", "account_id": "222", "start": "2021-03-01T00:00:00Z", "end": "2024-03-01T00:00:00Z", "hourly_commitment": 2.5, "utilization": 97}]`))
+	assert.NoError(t, err)
+	if assert.Len(t, commitments, 1) {
+		assert.Equal(t, CommitmentSavingsPlan, commitments[0].Kind)
+		assert.Equal(t, 36, commitments[0].TermMonths())
+	}
+}
+
+func TestClientPlanRenewals(t *testing.T) {
+	end := time.Now().UTC().AddDate(0, 0, 20).Format(time.RFC3339)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/api/search.json":
+			if r.URL.Query().Get("name") == AssetTypeAwsReservedInstance {
+				w.Write([]byte(fmt.Sprintf(`[{"reservation_id": "r-1", "instance_type": "m5.large", "instance_count": 1, "usage_price": 0.07, "utilization": 100, "end": "%s", "account": {"owner_id": "123456789012", "name": "Production"}}]`, end)))
+				return
+			}
+			w.Write([]byte(`[]`))
+		case "/olap_reports/cost/history":
+			assert.Equal(t, []string{DimensionTime, DimensionAWSAccount}, r.URL.Query()["dimensions[]"])
+			w.Write([]byte(`{
+				"report": "cost/history",
+				"status": "complete",
+				"interval": "monthly",
+				"dimensions": [{"time": [{"name": "2022-01"}]}, {"AWS-Account": [{"name": "1", "label": "Production"}]}],
+				"measures": [{"name": "cost"}],
+				"data": [[[511.0]]]
+			}`))
+		default:
+			t.Errorf("Unexpected request to ‘%s’", r.URL.EscapedPath())
+		}
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+
+	candidates, err := c.PlanRenewals(context.Background(), nil)
+	assert.NoError(t, err)
+	if assert.Len(t, candidates, 1) {
+		assert.Equal(t, "r-1", candidates[0].Commitment.ID)
+		assert.InDelta(t, 511, candidates[0].AccountMonthlyCost, 0.001)
+		assert.InDelta(t, 0.0429, candidates[0].ExposureShare, 0.0001)
+	}
+}
@@ -0,0 +1,384 @@
+package cloudhealth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default time-to-live of cached reports.
+const (
+	DefaultReportCacheOpenPeriodTTL   = time.Hour
+	DefaultReportCacheClosedPeriodTTL = 30 * 24 * time.Hour
+)
+
+// ReportCache stores report responses by key. Implementations must be safe for concurrent use.
+type ReportCache interface {
+	// Get returns the value stored for the key, and false when it is missing or expired.
+	Get(key string) ([]byte, bool, error)
+	// Set stores the value for the key until the ttl elapses.
+	Set(key string, value []byte, ttl time.Duration) error
+	// Delete removes the value stored for the key, if any.
+	Delete(key string) error
+	// Clear removes all values.
+	Clear() error
+}
+
+// ReportCaching configures the optional caching of report responses. Reports covering only months
+// before the current one are cached for ClosedPeriodTTL once the bill drops of the response mark
+// their last month as final, all others for OpenPeriodTTL.
+type ReportCaching struct {
+	Cache           ReportCache
+	OpenPeriodTTL   time.Duration
+	ClosedPeriodTTL time.Duration
+}
+
+// MemoryReportCache is a ReportCache keeping values in memory.
+type MemoryReportCache struct {
+	mutex   sync.Mutex
+	entries map[string]reportCacheEntry
+}
+
+// FileReportCache is a ReportCache keeping values in files of a directory, so they survive restarts.
+type FileReportCache struct {
+	mutex     sync.Mutex
+	directory string
+}
+
+// reportCacheEntry is a cached value with its expiration time.
+type reportCacheEntry struct {
+	Value     []byte    `json:"value"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewMemoryReportCache returns an empty in-memory ReportCache.
+func NewMemoryReportCache() *MemoryReportCache {
+	return &MemoryReportCache{entries: make(map[string]reportCacheEntry)}
+}
+
+// Get returns the value stored for the key, and false when it is missing or expired.
+func (c *MemoryReportCache) Get(key string) ([]byte, bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if time.Now().After(entry.ExpiresAt) {
+		delete(c.entries, key)
+		return nil, false, nil
+	}
+
+	return entry.Value, true, nil
+}
+
+// Set stores the value for the key until the ttl elapses.
+func (c *MemoryReportCache) Set(key string, value []byte, ttl time.Duration) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries[key] = reportCacheEntry{Value: value, ExpiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// Delete removes the value stored for the key, if any.
+func (c *MemoryReportCache) Delete(key string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.entries, key)
+	return nil
+}
+
+// Clear removes all values.
+func (c *MemoryReportCache) Clear() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries = make(map[string]reportCacheEntry)
+	return nil
+}
+
+// NewFileReportCache returns a ReportCache storing values in the directory, creating it if needed.
+func NewFileReportCache(directory string) (*FileReportCache, error) {
+	err := os.MkdirAll(directory, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileReportCache{directory: directory}, nil
+}
+
+// path returns the file storing the value of the key.
+func (c *FileReportCache) path(key string) string {
+	return filepath.Join(c.directory, key+".json")
+}
+
+// Get returns the value stored for the key, and false when it is missing or expired.
+func (c *FileReportCache) Get(key string) ([]byte, bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	content, err := ioutil.ReadFile(c.path(key))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var entry reportCacheEntry
+	err = json.Unmarshal(content, &entry)
+	if err != nil {
+		return nil, false, err
+	}
+	if time.Now().After(entry.ExpiresAt) {
+		return nil, false, os.Remove(c.path(key))
+	}
+
+	return entry.Value, true, nil
+}
+
+// Set stores the value for the key until the ttl elapses.
+func (c *FileReportCache) Set(key string, value []byte, ttl time.Duration) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	content, err := json.Marshal(reportCacheEntry{Value: value, ExpiresAt: time.Now().Add(ttl)})
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never see a partial entry
+	temporary, err := ioutil.TempFile(c.directory, "tmp-")
+	if err != nil {
+		return err
+	}
+	_, err = temporary.Write(content)
+	if err != nil {
+		temporary.Close()
+		os.Remove(temporary.Name())
+		return err
+	}
+	err = temporary.Close()
+	if err != nil {
+		os.Remove(temporary.Name())
+		return err
+	}
+
+	return os.Rename(temporary.Name(), c.path(key))
+}
+
+// Delete removes the value stored for the key, if any.
+func (c *FileReportCache) Delete(key string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	err := os.Remove(c.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
+
+// Clear removes all values.
+func (c *FileReportCache) Clear() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	files, err := filepath.Glob(filepath.Join(c.directory, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		err = os.Remove(file)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reportCacheKey returns the cache key of a report request of the client. Query parameters are normalized
+// so that equivalent requests share a key; the order of dimensions is kept as it changes the shape of the data.
+// The scope pins relative time indices to the period they were resolved in. The endpoint and API key of the
+// client are part of the key, so that clients of different tenants sharing a cache never read each other's reports.
+func reportCacheKey(s *Client, relativeURL string, scope string) (string, error) {
+	parts := strings.SplitN(relativeURL, "?", 2)
+
+	normalized := parts[0]
+	if len(parts) == 2 {
+		params, err := url.ParseQuery(parts[1])
+		if err != nil {
+			return "", err
+		}
+		sort.Strings(params["filters[]"])
+		sort.Strings(params["measures[]"])
+		normalized = fmt.Sprintf("%s?%s", normalized, params.Encode())
+	}
+
+	tenant := sha256.Sum256([]byte(s.EndpointURL + "#" + s.APIKey))
+	hash := sha256.Sum256([]byte(hex.EncodeToString(tenant[:]) + "#" + normalized + "#" + scope))
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// cacheScope returns the scope of the cache key of a report covering the TimeRange, and the first and last
+// instants it covers when it only covers months before the current one, or zero times otherwise.
+func (t TimeRange) cacheScope(interval Interval, now time.Time) (string, time.Time, time.Time) {
+	if t.IsZero() || interval.Validate() != nil {
+		return now.Format("2006-01-02T15"), time.Time{}, time.Time{}
+	}
+
+	current := periodIndex(interval, now)
+	scope := fmt.Sprintf("%s:%d", interval, current)
+	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	if t.kind == timeRangeIndices {
+		first, last := t.indices[0], t.indices[0]
+		for _, index := range t.indices {
+			if index < first {
+				first = index
+			}
+			if index > last {
+				last = index
+			}
+		}
+		end := periodStartTime(interval, current+int64(last)+1)
+		if end.After(startOfMonth) {
+			return scope, time.Time{}, time.Time{}
+		}
+		return scope, periodStartTime(interval, current+int64(first)), end.Add(-time.Nanosecond)
+	}
+
+	from, to, err := t.bounds(now)
+	if err != nil || !to.Before(startOfMonth) {
+		return scope, time.Time{}, time.Time{}
+	}
+
+	return scope, from, to
+}
+
+// isReportFinal reports whether the bill drops of a report response mark every month from the one containing
+// first to the one containing last as final, for every cloud they include. Responses without bill drops are
+// never final.
+func isReportFinal(responseBody []byte, first time.Time, last time.Time) bool {
+	var report struct {
+		BillDropInfo BillDrops `json:"bill_drop_info"`
+	}
+	err := json.Unmarshal(responseBody, &report)
+	if err != nil || len(report.BillDropInfo) == 0 {
+		return false
+	}
+
+	first = time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for _, drop := range report.BillDropInfo {
+		for month := first; !month.After(last); month = month.AddDate(0, 1, 0) {
+			if !report.BillDropInfo.IsPeriodFinal(CloudProvider(drop.Cloud), month) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// periodStartTime returns the first instant of the period with the given absolute number, the inverse of periodIndex.
+func periodStartTime(interval Interval, index int64) time.Time {
+	switch interval {
+	case IntervalHourly:
+		return time.Unix(index*3600, 0).UTC()
+	case IntervalDaily:
+		return time.Unix(index*86400, 0).UTC()
+	case IntervalWeekly:
+		return time.Unix((index*7-4)*86400, 0).UTC()
+	default:
+		return time.Date(int(index/12), time.Month(index%12+1), 1, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// getCachedReportResponse gets a report through the report cache, when one is configured.
+func getCachedReportResponse(s *Client, relativeURL string, timeRange TimeRange, interval Interval, fetch func() ([]byte, error)) ([]byte, error) {
+	caching := s.ReportCaching
+	if caching.Cache == nil {
+		return fetch()
+	}
+
+	scope, first, last := timeRange.cacheScope(interval, time.Now().UTC())
+	key, err := reportCacheKey(s, relativeURL, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, ok, err := caching.Cache.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return responseBody, nil
+	}
+
+	responseBody, err = fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := caching.OpenPeriodTTL
+	if ttl <= 0 {
+		ttl = DefaultReportCacheOpenPeriodTTL
+	}
+	if !last.IsZero() && isReportFinal(responseBody, first, last) {
+		ttl = caching.ClosedPeriodTTL
+		if ttl <= 0 {
+			ttl = DefaultReportCacheClosedPeriodTTL
+		}
+	}
+
+	err = caching.Cache.Set(key, responseBody, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return responseBody, nil
+}
+
+// InvalidateOLAPReportCache removes the cached response of an OLAP report request, if any.
+func (s *Client) InvalidateOLAPReportCache(report string, requestOptions *OLAPReportRequestOptions) error {
+	if s.ReportCaching.Cache == nil {
+		return nil
+	}
+
+	query, err := requestOptions.encode(strings.HasSuffix(report, "/history"))
+	if err != nil {
+		return err
+	}
+
+	scope, _, _ := requestOptions.TimeRange.cacheScope(requestOptions.Interval, time.Now().UTC())
+	key, err := reportCacheKey(s, fmt.Sprintf("olap_reports/%s?%s", report, query), scope)
+	if err != nil {
+		return err
+	}
+
+	return s.ReportCaching.Cache.Delete(key)
+}
+
+// ClearReportCache removes all cached report responses.
+func (s *Client) ClearReportCache() error {
+	if s.ReportCaching.Cache == nil {
+		return nil
+	}
+
+	return s.ReportCaching.Cache.Clear()
+}
@@ -0,0 +1,212 @@
+package cloudhealth
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ttlReportCache is a MemoryReportCache recording the ttl of the values it stores.
+type ttlReportCache struct {
+	*MemoryReportCache
+	ttls []time.Duration
+}
+
+func (c *ttlReportCache) Set(key string, value []byte, ttl time.Duration) error {
+	c.ttls = append(c.ttls, ttl)
+	return c.MemoryReportCache.Set(key, value, ttl)
+}
+
+func TestReportCacheKey(t *testing.T) {
+	s := &Client{APIKey: "apiKey", EndpointURL: "https://chapi.cloudhealthtech.com/"}
+
+	a, err := reportCacheKey(s, "olap_reports/cost/history?filters[]=b:select:1&filters[]=a:select:2&interval=monthly", "monthly:1")
+	assert.NoError(t, err)
+	b, err := reportCacheKey(s, "olap_reports/cost/history?interval=monthly&filters[]=a:select:2&filters[]=b:select:1", "monthly:1")
+	assert.NoError(t, err)
+	c, err := reportCacheKey(s, "olap_reports/cost/history?interval=monthly&filters[]=a:select:2&filters[]=b:select:1", "monthly:2")
+	assert.NoError(t, err)
+
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+
+	// Clients of other tenants never share keys
+	other, err := reportCacheKey(&Client{APIKey: "otherKey", EndpointURL: s.EndpointURL}, "olap_reports/cost/history?filters[]=b:select:1&filters[]=a:select:2&interval=monthly", "monthly:1")
+	assert.NoError(t, err)
+	assert.NotEqual(t, a, other)
+	other, err = reportCacheKey(&Client{APIKey: s.APIKey, EndpointURL: "https://example.com/"}, "olap_reports/cost/history?filters[]=b:select:1&filters[]=a:select:2&interval=monthly", "monthly:1")
+	assert.NoError(t, err)
+	assert.NotEqual(t, a, other)
+}
+
+func TestTimeRangeCacheScope(t *testing.T) {
+	now := time.Date(2022, 3, 3, 10, 30, 0, 0, time.UTC)
+	startOfJanuary := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	endOfFebruary := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond)
+
+	_, first, last := LastMonths(2).cacheScope(IntervalMonthly, now)
+	assert.Equal(t, startOfJanuary, first)
+	assert.Equal(t, endOfFebruary, last)
+
+	_, first, last = TimeIndices(-3, -2).cacheScope(IntervalDaily, now)
+	assert.True(t, first.IsZero())
+	assert.True(t, last.IsZero())
+
+	_, first, last = TimeIndices(-3).cacheScope(IntervalDaily, now)
+	assert.Equal(t, time.Date(2022, 2, 28, 0, 0, 0, 0, time.UTC), first)
+	assert.Equal(t, endOfFebruary, last)
+
+	_, first, last = TimeIndices(-1, -2).cacheScope(IntervalMonthly, now)
+	assert.Equal(t, startOfJanuary, first)
+	assert.Equal(t, endOfFebruary, last)
+
+	_, _, last = MonthToDate().cacheScope(IntervalDaily, now)
+	assert.True(t, last.IsZero())
+}
+
+func TestIsReportFinal(t *testing.T) {
+	first := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	last := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond)
+
+	assert.True(t, isReportFinal([]byte(`{"bill_drop_info": [
+		{"cloud": "AWS", "billing_period": "2022-01", "final": true},
+		{"cloud": "AWS", "billing_period": "2022-02", "final": true}
+	]}`), first, last))
+
+	// Every month of the report must be final, not only the last one
+	assert.False(t, isReportFinal([]byte(`{"bill_drop_info": [
+		{"cloud": "AWS", "billing_period": "2022-02", "final": true}
+	]}`), first, last))
+
+	// A drop for a later month doesn't make a month final
+	assert.False(t, isReportFinal([]byte(`{"bill_drop_info": [
+		{"cloud": "AWS", "billing_period": "2022-02", "final": false},
+		{"cloud": "AWS", "billing_period": "2022-03", "final": false}
+	]}`), last, last))
+
+	assert.False(t, isReportFinal([]byte(`{"bill_drop_info": []}`), first, last))
+}
+
+func TestReportCacheClosedPeriodRequiresFinalBillDrop(t *testing.T) {
+	lastMonth := time.Now().UTC().AddDate(0, 0, -time.Now().UTC().Day()).Format("2006-01")
+	final := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"status": "complete", "bill_drop_info": [{"cloud": "AWS", "billing_period": "%s", "final": %t}]}`, lastMonth, final)
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+	cache := &ttlReportCache{MemoryReportCache: NewMemoryReportCache()}
+	c.ReportCaching = ReportCaching{Cache: cache}
+
+	options := &OLAPReportRequestOptions{Interval: IntervalMonthly, TimeRange: LastMonths(1), Measures: []string{MeasureCost}}
+	_, err = c.GetOLAPReport(OLAPReportCostHistory, options)
+	assert.NoError(t, err)
+
+	final = true
+	assert.NoError(t, c.InvalidateOLAPReportCache(OLAPReportCostHistory, options))
+	_, err = c.GetOLAPReport(OLAPReportCostHistory, options)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []time.Duration{DefaultReportCacheOpenPeriodTTL, DefaultReportCacheClosedPeriodTTL}, cache.ttls)
+}
+
+func TestReportCacheLaterBillDropKeepsPeriodOpen(t *testing.T) {
+	now := time.Now().UTC()
+	lastMonth := now.AddDate(0, 0, -now.Day()).Format("2006-01")
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"status": "complete", "bill_drop_info": [
+			{"cloud": "AWS", "billing_period": "%s", "final": false},
+			{"cloud": "AWS", "billing_period": "%s", "final": false}
+		]}`, lastMonth, now.Format("2006-01"))
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+	cache := &ttlReportCache{MemoryReportCache: NewMemoryReportCache()}
+	c.ReportCaching = ReportCaching{Cache: cache}
+
+	options := &OLAPReportRequestOptions{Interval: IntervalMonthly, TimeRange: LastMonths(1), Measures: []string{MeasureCost}}
+	_, err = c.GetOLAPReport(OLAPReportCostHistory, options)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []time.Duration{DefaultReportCacheOpenPeriodTTL}, cache.ttls)
+}
+
+func TestGetBillDropsBypassesCache(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"status": "complete", "bill_drop_info": []}`)
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+	c.ReportCaching = ReportCaching{Cache: NewMemoryReportCache()}
+
+	for i := 0; i < 2; i++ {
+		_, err = c.GetBillDrops(context.Background())
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2, requests)
+}
+
+func TestFileReportCache(t *testing.T) {
+	directory, err := ioutil.TempDir("", "report-cache")
+	assert.NoError(t, err)
+	defer os.RemoveAll(directory)
+
+	cache, err := NewFileReportCache(directory)
+	assert.NoError(t, err)
+
+	assert.NoError(t, cache.Set("a", []byte("report"), time.Hour))
+	value, ok, err := cache.Get("a")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("report"), value)
+
+	assert.NoError(t, cache.Set("b", []byte("expired"), -time.Second))
+	_, ok, err = cache.Get("b")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, cache.Clear())
+	_, ok, err = cache.Get("a")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestGetOLAPReportCached(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"status": "complete"}`)
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+	c.ReportCaching = ReportCaching{Cache: NewMemoryReportCache()}
+
+	options := &OLAPReportRequestOptions{Interval: IntervalMonthly, TimeRange: LastMonths(1), Measures: []string{MeasureCost}}
+	for i := 0; i < 2; i++ {
+		_, err = c.GetOLAPReport(OLAPReportCostHistory, options)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 1, requests)
+
+	assert.NoError(t, c.InvalidateOLAPReportCache(OLAPReportCostHistory, options))
+	_, err = c.GetOLAPReport(OLAPReportCostHistory, options)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, requests)
+}
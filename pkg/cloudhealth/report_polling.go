@@ -0,0 +1,107 @@
+package cloudhealth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrReportNotReady is returned when a report is still being processed by CloudHealth and polling is
+// disabled, or when polling gave up before the report completed.
+var ErrReportNotReady = errors.New("report is still being processed by CloudHealth")
+
+// Default settings used to poll reports that are still being processed.
+const (
+	DefaultReportPollInitialInterval = time.Second
+	DefaultReportPollMaxInterval     = 30 * time.Second
+	DefaultReportPollMaxWait         = 5 * time.Minute
+)
+
+// reportInProgressStatuses lists the report statuses meaning CloudHealth is still processing the report.
+var reportInProgressStatuses = map[string]bool{
+	"queued":      true,
+	"pending":     true,
+	"processing":  true,
+	"in_progress": true,
+	"running":     true,
+}
+
+// ReportPolling configures how reports still being processed by CloudHealth are re-requested.
+// The zero value polls with the default settings.
+type ReportPolling struct {
+	// Disabled returns ErrReportNotReady instead of polling.
+	Disabled bool
+	// InitialInterval is the wait before the first retry, doubled after every retry.
+	InitialInterval time.Duration
+	// MaxInterval caps the wait between two retries.
+	MaxInterval time.Duration
+	// MaxWait bounds the total time spent polling when the context has no deadline.
+	MaxWait time.Duration
+}
+
+// reportStatus is the part of a report response telling whether it has been fully processed.
+type reportStatus struct {
+	Status string `json:"status"`
+}
+
+// getReportResponse gets a report, re-requesting it with exponential backoff while CloudHealth is still processing it.
+// Every request reaches CloudHealth: the report cache is only consulted by callers, before polling starts.
+func getReportResponse(ctx context.Context, s *Client, relativeURL string) ([]byte, error) {
+	polling := s.ReportPolling
+	interval := polling.InitialInterval
+	if interval <= 0 {
+		interval = DefaultReportPollInitialInterval
+	}
+	maxInterval := polling.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = DefaultReportPollMaxInterval
+	}
+
+	// Don't poll forever when the caller didn't set a deadline
+	if _, ok := ctx.Deadline(); !ok && !polling.Disabled {
+		maxWait := polling.MaxWait
+		if maxWait <= 0 {
+			maxWait = DefaultReportPollMaxWait
+		}
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, maxWait)
+		defer cancel()
+	}
+
+	for {
+		responseBody, err := getResponsePageWithContext(ctx, s, relativeURL)
+		if err != nil {
+			return nil, err
+		}
+
+		var status reportStatus
+		err = json.Unmarshal(responseBody, &status)
+		if err != nil {
+			return nil, err
+		}
+
+		if !reportInProgressStatuses[status.Status] {
+			return responseBody, nil
+		}
+
+		if polling.Disabled {
+			return nil, ErrReportNotReady
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("%w: %s", ErrReportNotReady, ctx.Err())
+		case <-timer.C:
+		}
+
+		interval *= 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package cloudhealth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newProcessingReportServer(processingResponses int) (*httptest.Server, *int) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests <= processingResponses {
+			fmt.Fprint(w, `{"status": "processing"}`)
+			return
+		}
+		fmt.Fprint(w, `{"status": "complete", "measures": [{"name": "cost"}], "data": [[1.0]], "dimensions": [{"AWS-Service-Category": [{"name": "ec2"}]}]}`)
+	}))
+
+	return ts, &requests
+}
+
+func TestGetAWSCostHistoryReportPolling(t *testing.T) {
+	ts, requests := newProcessingReportServer(2)
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+	c.ReportPolling = ReportPolling{InitialInterval: time.Millisecond, MaxInterval: 2 * time.Millisecond}
+
+	report, err := c.GetAWSCostHistoryReport(&AWSCostHistoryRequestOptions{Measures: MeasureCost, Interval: IntervalMonthly, TimeRange: TimeIndices(-1)})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, *requests)
+	assert.Equal(t, "complete", report.Status)
+}
+
+func TestGetOLAPReportPollingDisabled(t *testing.T) {
+	ts, requests := newProcessingReportServer(1)
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+	c.ReportPolling = ReportPolling{Disabled: true}
+
+	_, err = c.GetCurrentCostReport(&CurrentCostRequestOptions{})
+	assert.Equal(t, ErrReportNotReady, err)
+	assert.Equal(t, 1, *requests)
+}
+
+func TestGetOLAPReportPollingDeadline(t *testing.T) {
+	ts, _ := newProcessingReportServer(1000)
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+	c.ReportPolling = ReportPolling{InitialInterval: time.Millisecond}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = c.GetOLAPReportWithContext(ctx, OLAPReportCostCurrent, &OLAPReportRequestOptions{Measures: []string{MeasureCost}})
+	assert.True(t, errors.Is(err, ErrReportNotReady))
+}
@@ -1,26 +1,29 @@
 package cloudhealth
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"time"
 )
 
 // AWSCostHistoryRequestOptions represents the possible options to specify when making a request against the cost history report
 type AWSCostHistoryRequestOptions struct {
-	interval           string
-	measures           string
-	clientApiId        string
-	selectedDimensions string
-	rejectedDimensions string
-	targetAWSAccountId string
-	time               string
+	Interval           Interval
+	Measures           string
+	ClientAPIID        string
+	SelectedDimensions string
+	RejectedDimensions string
+	TargetAWSAccountID string
+	TimeRange          TimeRange
+	Filters            []OLAPFilter
 }
 
 // AWSCostHistoryReport represents the details of a Cost History Report for the AWS Service Category in CloudHealth
 type AWSCostHistoryReport struct {
-	BillDropInfo         []interface{}                    `json:"bill_drop_info"`
+	BillDropInfo         BillDrops                        `json:"bill_drop_info"`
 	CubeID               string                           `json:"cube_id"`
 	Data                 [][]float64                      `json:"data"`
 	Dimensions           []AWSCostHistoryReportDimensions `json:"dimensions"`
@@ -69,46 +72,59 @@ type AWSCostHistoryReportMeasuresMetadata struct {
 
 // GetAWSCostHistoryReport gets the Cost History Report
 func (s *Client) GetAWSCostHistoryReport(requestOptions *AWSCostHistoryRequestOptions) (*AWSCostHistoryReport, error) {
+	return s.GetAWSCostHistoryReportWithContext(context.Background(), requestOptions)
+}
+
+// GetAWSCostHistoryReportWithContext gets the Cost History Report, polling while CloudHealth is processing it
+// until it completes or the context is done.
+func (s *Client) GetAWSCostHistoryReportWithContext(ctx context.Context, requestOptions *AWSCostHistoryRequestOptions) (*AWSCostHistoryReport, error) {
 	// Set up the base URL
 	relativeURL := fmt.Sprintf("olap_reports/cost/history?dimensions[]=AWS-Service-Category")
 
 	// Parse request options
-	if requestOptions.measures != "" {
-		relativeURL = fmt.Sprintf("%s&measures[]=%s", relativeURL, requestOptions.measures)
+	if requestOptions.Measures != "" {
+		relativeURL = fmt.Sprintf("%s&measures[]=%s", relativeURL, requestOptions.Measures)
 	} else {
 		return nil, errors.New("the `measures` property is required and cannot be blank")
 	}
 
-	if requestOptions.interval != "" {
-		relativeURL = fmt.Sprintf("%s&interval=%s", relativeURL, requestOptions.interval)
-	} else {
-		return nil, errors.New("the `interval` property is required and cannot be blank")
+	err := requestOptions.Interval.Validate()
+	if err != nil {
+		return nil, err
 	}
+	relativeURL = fmt.Sprintf("%s&interval=%s", relativeURL, requestOptions.Interval)
 
-	if requestOptions.time != "" {
-		relativeURL = fmt.Sprintf("%s&filters[]=time:select:%s", relativeURL, requestOptions.time)
-	} else {
-		return nil, errors.New("the `time` property is required and cannot be blank")
+	timeFilter, err := requestOptions.TimeRange.Encode(requestOptions.Interval)
+	if err != nil {
+		return nil, err
+	}
+	relativeURL = fmt.Sprintf("%s&filters[]=time:select:%s", relativeURL, timeFilter)
+
+	if requestOptions.ClientAPIID != "" {
+		relativeURL = fmt.Sprintf("%s&client_api_id=%s", relativeURL, requestOptions.ClientAPIID)
 	}
 
-	if requestOptions.clientApiId != "" {
-		relativeURL = fmt.Sprintf("%s&client_api_id=%s", relativeURL, requestOptions.clientApiId)
+	if requestOptions.SelectedDimensions != "" {
+		relativeURL = fmt.Sprintf("%s&filters[]=AWS-Service-Category:select:%s", relativeURL, requestOptions.SelectedDimensions)
 	}
 
-	if requestOptions.selectedDimensions != "" {
-		relativeURL = fmt.Sprintf("%s&filters[]=AWS-Service-Category:select:%s", relativeURL, requestOptions.selectedDimensions)
+	if requestOptions.RejectedDimensions != "" {
+		relativeURL = fmt.Sprintf("%s&filters[]=AWS-Service-Category:reject:%s", relativeURL, requestOptions.RejectedDimensions)
 	}
 
-	if requestOptions.rejectedDimensions != "" {
-		relativeURL = fmt.Sprintf("%s&filters[]=AWS-Service-Category:reject:%s", relativeURL, requestOptions.rejectedDimensions)
+	if requestOptions.TargetAWSAccountID != "" {
+		relativeURL = fmt.Sprintf("%s&filters[]=AWS-Account:select:%s", relativeURL, requestOptions.TargetAWSAccountID)
 	}
 
-	if requestOptions.targetAWSAccountId != "" {
-		relativeURL = fmt.Sprintf("%s&filters[]=AWS-Account:select:%s", relativeURL, requestOptions.targetAWSAccountId)
+	// Additional filters, such as Perspective groups
+	for _, filter := range requestOptions.Filters {
+		relativeURL = fmt.Sprintf("%s&filters[]=%s", relativeURL, url.QueryEscape(filter.String()))
 	}
 
-	// Make the API call
-	responseBody, err := getResponsePage(s, relativeURL)
+	// Make the API call, unless the response is cached
+	responseBody, err := getCachedReportResponse(s, relativeURL, requestOptions.TimeRange, requestOptions.Interval, func() ([]byte, error) {
+		return getReportResponse(ctx, s, relativeURL)
+	})
 	if err != nil {
 		fmt.Println("Error while calling CloudHealth API")
 		return nil, err
@@ -0,0 +1,140 @@
+package cloudhealth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+// RightsizingAssetType is the kind of asset rightsizing recommendations are computed for.
+type RightsizingAssetType string
+
+// Asset types with rightsizing recommendations.
+const (
+	RightsizingEC2 RightsizingAssetType = "aws_instance"
+	RightsizingRDS RightsizingAssetType = "aws_rds_instance"
+)
+
+// RightsizingRequestOptions selects rightsizing recommendations. Recommendations can be restricted to
+// AWS accounts, by owner ID, and to groups of a Perspective, such as the filter returned by PerspectiveFilter.
+type RightsizingRequestOptions struct {
+	AssetType   RightsizingAssetType
+	AccountIDs  []string
+	Perspective *OLAPFilter
+}
+
+// RightsizingUtilization represents the utilization the recommendation is based on, in percent over the lookback period.
+// Memory is only known for assets reporting it, such as through the Metrics API.
+type RightsizingUtilization struct {
+	LookbackDays  int      `json:"lookback_days"`
+	CPUAverage    float64  `json:"cpu_avg"`
+	CPUMax        float64  `json:"cpu_max"`
+	MemoryAverage *float64 `json:"memory_avg"`
+	MemoryMax     *float64 `json:"memory_max"`
+}
+
+// RightsizingRecommendation represents the recommended type of an asset and the savings it would bring.
+type RightsizingRecommendation struct {
+	AssetID                 string                 `json:"asset_id"`
+	AssetName               string                 `json:"asset_name"`
+	AccountID               string                 `json:"account_id"`
+	Region                  string                 `json:"region"`
+	CurrentType             string                 `json:"current_type"`
+	RecommendedType         string                 `json:"recommended_type"`
+	CurrentMonthlyCost      float64                `json:"current_monthly_cost"`
+	RecommendedMonthlyCost  float64                `json:"recommended_monthly_cost"`
+	ProjectedMonthlySavings float64                `json:"projected_monthly_savings"`
+	Utilization             RightsizingUtilization `json:"utilization"`
+}
+
+// rightsizingResponse is the body of a response of the rightsizing endpoint.
+type rightsizingResponse struct {
+	Recommendations []RightsizingRecommendation `json:"recommendations"`
+}
+
+// encode returns the query parameters of the options. Nil options are treated as zero options.
+func (o *RightsizingRequestOptions) encode() (url.Values, error) {
+	if o == nil {
+		o = &RightsizingRequestOptions{}
+	}
+
+	if o.AssetType != RightsizingEC2 && o.AssetType != RightsizingRDS {
+		return nil, fmt.Errorf("invalid rightsizing asset type `%s`", o.AssetType)
+	}
+
+	params := url.Values{"asset_type": {string(o.AssetType)}}
+	for _, accountID := range o.AccountIDs {
+		params.Add("account_ids[]", accountID)
+	}
+	if o.Perspective != nil {
+		params.Set("perspective_id", o.Perspective.Dimension)
+		for _, member := range o.Perspective.Members {
+			params.Add("group_ids[]", member)
+		}
+	}
+
+	return params, nil
+}
+
+// GetRightsizingRecommendations gets the rightsizing recommendations matching the options, sorted by projected savings, largest first.
+func (s *Client) GetRightsizingRecommendations(ctx context.Context, requestOptions *RightsizingRequestOptions) ([]RightsizingRecommendation, error) {
+	params, err := requestOptions.encode()
+	if err != nil {
+		return nil, err
+	}
+
+	// Set variables we will need along the way
+	var recommendations []RightsizingRecommendation
+	var page, pageSize int = 1, 100
+
+	// Loop for paging
+	for {
+		// Set up the query parameters for the API
+		params.Set("page", strconv.Itoa(page))
+		params.Set("per_page", strconv.Itoa(pageSize))
+
+		// Set up the URL
+		relativeURL := fmt.Sprintf("v1/rightsizing/recommendations?%s", params.Encode())
+
+		// Make the API call
+		responseBody, err := getResponsePageWithContext(ctx, s, relativeURL)
+		if err != nil {
+			return nil, err
+		}
+
+		// Unmarshal the response data into the page of recommendations
+		var response rightsizingResponse
+		err = json.Unmarshal(responseBody, &response)
+		if err != nil {
+			return nil, err
+		}
+		recommendations = append(recommendations, response.Recommendations...)
+
+		// Check length of the page to determine if we should break out of the loop
+		if len(response.Recommendations) < pageSize {
+			break
+		}
+
+		// Increment page counter
+		page++
+	}
+
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].ProjectedMonthlySavings > recommendations[j].ProjectedMonthlySavings
+	})
+
+	return recommendations, nil
+}
+
+// TotalProjectedSavings returns the sum of the projected monthly savings of the recommendations.
+func TotalProjectedSavings(recommendations []RightsizingRecommendation) float64 {
+	var total float64
+	for _, recommendation := range recommendations {
+		total += recommendation.ProjectedMonthlySavings
+	}
+
+	return total
+}
@@ -0,0 +1,50 @@
+package cloudhealth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRightsizingRecommendations(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/rightsizing/recommendations", r.URL.EscapedPath())
+		query := r.URL.Query()
+		assert.Equal(t, "aws_instance", query.Get("asset_type"))
+		assert.Equal(t, []string{"111", "222"}, query["account_ids[]"])
+		assert.Equal(t, "1234", query.Get("perspective_id"))
+		assert.Equal(t, []string{"5678"}, query["group_ids[]"])
+
+		w.Write([]byte(`{"recommendations": [
+			{"asset_id": "i-1", "current_type": "m5.xlarge", "recommended_type": "m5.large", "projected_monthly_savings": 70.08, "utilization": {"lookback_days": 30, "cpu_avg": 8.5, "cpu_max": 31}},
+			{"asset_id": "i-2", "current_type": "m5.4xlarge", "recommended_type": "m5.2xlarge", "projected_monthly_savings": 280.32, "utilization": {"lookback_days": 30, "cpu_avg": 12, "cpu_max": 40, "memory_avg": 22.5, "memory_max": 35}}
+		]}`))
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+
+	recommendations, err := c.GetRightsizingRecommendations(context.Background(), &RightsizingRequestOptions{
+		AssetType:   RightsizingEC2,
+		AccountIDs:  []string{"111", "222"},
+		Perspective: &OLAPFilter{Dimension: "1234", Members: []string{"5678"}},
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, recommendations, 2) {
+		assert.Equal(t, "i-2", recommendations[0].AssetID)
+		assert.Equal(t, 22.5, *recommendations[0].Utilization.MemoryAverage)
+		assert.Nil(t, recommendations[1].Utilization.MemoryAverage)
+	}
+	assert.InDelta(t, 350.40, TotalProjectedSavings(recommendations), 0.001)
+
+	_, err = c.GetRightsizingRecommendations(context.Background(), &RightsizingRequestOptions{AssetType: "aws_volume"})
+	assert.EqualError(t, err, "invalid rightsizing asset type `aws_volume`")
+
+	_, err = c.GetRightsizingRecommendations(context.Background(), nil)
+	assert.EqualError(t, err, "invalid rightsizing asset type ``")
+}
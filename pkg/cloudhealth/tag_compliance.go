@@ -0,0 +1,333 @@
+package cloudhealth
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// Reasons a resource violates a tag rule.
+const (
+	TagViolationMissing         = "missing"
+	TagViolationValueNotAllowed = "value_not_allowed"
+	TagViolationPatternMismatch = "pattern_mismatch"
+)
+
+// TagRule represents a constraint on a tag key: whether it is required, and the values it may take,
+// listed in AllowedValues or matching Pattern. Rules of tags that are not required only apply when present.
+type TagRule struct {
+	Key           string   `json:"key"`
+	Required      bool     `json:"required"`
+	AllowedValues []string `json:"allowed_values,omitempty"`
+	Pattern       string   `json:"pattern,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// TagPolicy represents the tag rules resources must comply with. Rules apply to every asset type,
+// AssetTypeRules to resources of one asset type only.
+type TagPolicy struct {
+	Rules          []TagRule            `json:"rules"`
+	AssetTypeRules map[string][]TagRule `json:"asset_type_rules,omitempty"`
+}
+
+// TaggedResource represents the tags of an asset, such as a record of an asset search or of a local export.
+type TaggedResource struct {
+	AssetType   string `json:"asset_type"`
+	AssetID     string `json:"asset_id"`
+	AccountID   string `json:"account_id"`
+	AccountName string `json:"account_name"`
+	Tags        Tags   `json:"tags"`
+}
+
+// TagViolation represents a tag rule a resource doesn't comply with.
+type TagViolation struct {
+	AssetID string `json:"asset_id"`
+	Key     string `json:"key"`
+	Reason  string `json:"reason"`
+	Value   string `json:"value,omitempty"`
+}
+
+// TagComplianceGroup represents the compliance of the resources of an asset type in an account.
+type TagComplianceGroup struct {
+	AccountID   string         `json:"account_id"`
+	AccountName string         `json:"account_name"`
+	AssetType   string         `json:"asset_type"`
+	Total       int            `json:"total"`
+	Compliant   int            `json:"compliant"`
+	Violations  []TagViolation `json:"violations"`
+}
+
+// TagComplianceReport represents the compliance of resources with a TagPolicy, per account and asset type.
+type TagComplianceReport struct {
+	Total     int                  `json:"total"`
+	Compliant int                  `json:"compliant"`
+	Groups    []TagComplianceGroup `json:"groups"`
+}
+
+// compile returns a copy of the policy with the patterns of its rules compiled.
+func (p TagPolicy) compile() (TagPolicy, error) {
+	compileRules := func(rules []TagRule) ([]TagRule, error) {
+		compiled := append([]TagRule{}, rules...)
+		for i := range compiled {
+			if compiled[i].Key == "" {
+				return nil, fmt.Errorf("tag rule %d has no key", i)
+			}
+			if compiled[i].Pattern == "" {
+				continue
+			}
+			pattern, err := regexp.Compile(compiled[i].Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern of tag rule `%s`: %w", compiled[i].Key, err)
+			}
+			compiled[i].pattern = pattern
+		}
+		return compiled, nil
+	}
+
+	compiled := TagPolicy{AssetTypeRules: make(map[string][]TagRule, len(p.AssetTypeRules))}
+	var err error
+	compiled.Rules, err = compileRules(p.Rules)
+	if err != nil {
+		return compiled, err
+	}
+	for assetType, rules := range p.AssetTypeRules {
+		compiled.AssetTypeRules[assetType], err = compileRules(rules)
+		if err != nil {
+			return compiled, err
+		}
+	}
+
+	return compiled, nil
+}
+
+// check returns the violation of the rule by the resource, if any.
+func (r TagRule) check(resource TaggedResource) *TagViolation {
+	value, ok := resource.Tags.Get(r.Key)
+	if !ok {
+		if r.Required {
+			return &TagViolation{AssetID: resource.AssetID, Key: r.Key, Reason: TagViolationMissing}
+		}
+		return nil
+	}
+
+	if len(r.AllowedValues) > 0 {
+		allowed := false
+		for _, allowedValue := range r.AllowedValues {
+			if value == allowedValue {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return &TagViolation{AssetID: resource.AssetID, Key: r.Key, Reason: TagViolationValueNotAllowed, Value: value}
+		}
+	}
+
+	if r.pattern != nil && !r.pattern.MatchString(value) {
+		return &TagViolation{AssetID: resource.AssetID, Key: r.Key, Reason: TagViolationPatternMismatch, Value: value}
+	}
+
+	return nil
+}
+
+// AuditTags checks the resources against the policy and returns the compliance per account and asset type,
+// sorted by account and asset type.
+func AuditTags(policy TagPolicy, resources []TaggedResource) (*TagComplianceReport, error) {
+	policy, err := policy.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &TagComplianceReport{}
+	groups := make(map[[2]string]*TagComplianceGroup)
+
+	for _, resource := range resources {
+		key := [2]string{resource.AccountID, resource.AssetType}
+		group, ok := groups[key]
+		if !ok {
+			group = &TagComplianceGroup{AccountID: resource.AccountID, AccountName: resource.AccountName, AssetType: resource.AssetType}
+			groups[key] = group
+		}
+
+		compliant := true
+		rules := append(append([]TagRule{}, policy.Rules...), policy.AssetTypeRules[resource.AssetType]...)
+		for _, rule := range rules {
+			violation := rule.check(resource)
+			if violation != nil {
+				group.Violations = append(group.Violations, *violation)
+				compliant = false
+			}
+		}
+
+		group.Total++
+		report.Total++
+		if compliant {
+			group.Compliant++
+			report.Compliant++
+		}
+	}
+
+	for _, group := range groups {
+		report.Groups = append(report.Groups, *group)
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		if report.Groups[i].AccountID != report.Groups[j].AccountID {
+			return report.Groups[i].AccountID < report.Groups[j].AccountID
+		}
+		return report.Groups[i].AssetType < report.Groups[j].AssetType
+	})
+
+	return report, nil
+}
+
+// ComplianceRate returns the share of compliant resources, between 0 and 1. It is 1 when there are no resources.
+func (r *TagComplianceReport) ComplianceRate() float64 {
+	if r.Total == 0 {
+		return 1
+	}
+
+	return float64(r.Compliant) / float64(r.Total)
+}
+
+// ComplianceRate returns the share of compliant resources of the group, between 0 and 1.
+func (g TagComplianceGroup) ComplianceRate() float64 {
+	if g.Total == 0 {
+		return 1
+	}
+
+	return float64(g.Compliant) / float64(g.Total)
+}
+
+// WriteJSON writes the report as JSON.
+func (r *TagComplianceReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(r)
+}
+
+// WriteSummaryCSV writes one CSV row per account and asset type, with the number of compliant resources.
+func (r *TagComplianceReport) WriteSummaryCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	err := writer.Write([]string{"account_id", "account_name", "asset_type", "total", "compliant", "compliance_rate"})
+	if err != nil {
+		return err
+	}
+
+	for _, group := range r.Groups {
+		err = writer.Write([]string{
+			group.AccountID,
+			group.AccountName,
+			group.AssetType,
+			strconv.Itoa(group.Total),
+			strconv.Itoa(group.Compliant),
+			strconv.FormatFloat(group.ComplianceRate(), 'f', 4, 64),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteCSV writes one CSV row per violation.
+func (r *TagComplianceReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	err := writer.Write([]string{"account_id", "account_name", "asset_type", "asset_id", "key", "reason", "value"})
+	if err != nil {
+		return err
+	}
+
+	for _, group := range r.Groups {
+		for _, violation := range group.Violations {
+			err = writer.Write([]string{group.AccountID, group.AccountName, group.AssetType, violation.AssetID, violation.Key, violation.Reason, violation.Value})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// LoadTaggedResources reads resources from a local JSON export, an array of TaggedResource.
+func LoadTaggedResources(r io.Reader) ([]TaggedResource, error) {
+	var resources []TaggedResource
+	err := json.NewDecoder(r).Decode(&resources)
+	if err != nil {
+		return nil, err
+	}
+
+	return resources, nil
+}
+
+// TaggedResourcesFromAssets returns the tags of assets of the asset type returned by an asset search
+// including the `account` relation. Tags are read from the `tags` field, as a list of key/value pairs or a map.
+func TaggedResourcesFromAssets(assetType string, assets []Asset) []TaggedResource {
+	resources := make([]TaggedResource, len(assets))
+	for i, asset := range assets {
+		resource := TaggedResource{AssetType: assetType, AssetID: assetValue(asset["id"])}
+		if account, ok := asset["account"].(map[string]interface{}); ok {
+			resource.AccountID = assetValue(account["owner_id"])
+			resource.AccountName = assetValue(account["name"])
+		}
+
+		switch tags := asset["tags"].(type) {
+		case []interface{}:
+			for _, tag := range tags {
+				if pair, ok := tag.(map[string]interface{}); ok {
+					resource.Tags.Set(assetValue(pair["key"]), assetValue(pair["value"]))
+				}
+			}
+		case map[string]interface{}:
+			values := make(map[string]string, len(tags))
+			for key, value := range tags {
+				values[key] = assetValue(value)
+			}
+			resource.Tags = TagsFromMap(values)
+		}
+
+		resources[i] = resource
+	}
+
+	return resources
+}
+
+// assetValue formats a field of an asset as text.
+func assetValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// AuditAssetTags searches the active assets of the asset types, such as AwsInstance, and checks their tags against the policy.
+func (s *Client) AuditAssetTags(ctx context.Context, policy TagPolicy, assetTypes ...string) (*TagComplianceReport, error) {
+	var resources []TaggedResource
+	for _, assetType := range assetTypes {
+		assets, err := s.SearchAssets(ctx, assetType, NewAssetQuery().Active(true).Include("account"))
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, TaggedResourcesFromAssets(assetType, assets)...)
+	}
+
+	return AuditTags(policy, resources)
+}
@@ -0,0 +1,89 @@
+package cloudhealth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testTagPolicy = TagPolicy{
+	Rules: []TagRule{
+		{Key: "env", Required: true, AllowedValues: []string{"prod", "staging"}},
+		{Key: "cost-center", Pattern: `^CC-[0-9]{4}$`},
+	},
+	AssetTypeRules: map[string][]TagRule{
+		"AwsInstance": {{Key: "owner", Required: true}},
+	},
+}
+
+func TestAuditTags(t *testing.T) {
+	resources := []TaggedResource{
+		{AssetType: "AwsInstance", AssetID: "1", AccountID: "111", AccountName: "prod", Tags: Tags{{Key: "env", Value: "prod"}, {Key: "owner", Value: "payments"}}},
+		{AssetType: "AwsInstance", AssetID: "2", AccountID: "111", AccountName: "prod", Tags: Tags{{Key: "env", Value: "dev"}, {Key: "cost-center", Value: "1234"}}},
+		{AssetType: "AwsVolume", AssetID: "3", AccountID: "111", AccountName: "prod", Tags: Tags{{Key: "env", Value: "prod"}, {Key: "cost-center", Value: "CC-1234"}}},
+		{AssetType: "AwsVolume", AssetID: "4", AccountID: "000", AccountName: "sandbox"},
+	}
+
+	report, err := AuditTags(testTagPolicy, resources)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, report.Total)
+	assert.Equal(t, 2, report.Compliant)
+	assert.Equal(t, 0.5, report.ComplianceRate())
+
+	if assert.Len(t, report.Groups, 3) {
+		assert.Equal(t, "000", report.Groups[0].AccountID)
+		assert.Equal(t, []TagViolation{{AssetID: "4", Key: "env", Reason: TagViolationMissing}}, report.Groups[0].Violations)
+
+		assert.Equal(t, "AwsInstance", report.Groups[1].AssetType)
+		assert.Equal(t, 1, report.Groups[1].Compliant)
+		assert.Equal(t, []TagViolation{
+			{AssetID: "2", Key: "env", Reason: TagViolationValueNotAllowed, Value: "dev"},
+			{AssetID: "2", Key: "cost-center", Reason: TagViolationPatternMismatch, Value: "1234"},
+			{AssetID: "2", Key: "owner", Reason: TagViolationMissing},
+		}, report.Groups[1].Violations)
+	}
+
+	var buffer bytes.Buffer
+	assert.NoError(t, report.WriteSummaryCSV(&buffer))
+	assert.Equal(t, "account_id,account_name,asset_type,total,compliant,compliance_rate\n000,sandbox,AwsVolume,1,0,0.0000\n111,prod,AwsInstance,2,1,0.5000\n111,prod,AwsVolume,1,1,1.0000\n", buffer.String())
+
+	buffer.Reset()
+	assert.NoError(t, report.WriteCSV(&buffer))
+	assert.Equal(t, 5, strings.Count(buffer.String(), "\n"))
+
+	_, err = AuditTags(TagPolicy{Rules: []TagRule{{Key: "env", Pattern: "("}}}, resources)
+	assert.Error(t, err)
+}
+
+func TestAuditAssetTags(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "account", r.URL.Query().Get("include"))
+		w.Write([]byte(`[
+			{"id": 1, "account": {"owner_id": "111", "name": "prod"}, "tags": [{"key": "env", "value": "prod"}, {"key": "owner", "value": "payments"}]},
+			{"id": 2, "account": {"owner_id": "111", "name": "prod"}, "tags": {"env": "qa"}}
+		]`))
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("apiKey", fmt.Sprintf("%s/", ts.URL))
+	assert.NoError(t, err)
+
+	report, err := c.AuditAssetTags(context.Background(), testTagPolicy, "AwsInstance")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Compliant)
+	if assert.Len(t, report.Groups, 1) {
+		assert.Equal(t, TagViolation{AssetID: "2", Key: "env", Reason: TagViolationValueNotAllowed, Value: "qa"}, report.Groups[0].Violations[0])
+	}
+
+	resources, err := LoadTaggedResources(strings.NewReader(`[{"asset_type": "AwsVolume", "asset_id": "vol-1", "account_id": "111", "tags": [{"key": "env", "value": "prod"}]}]`))
+	assert.NoError(t, err)
+	report, err = AuditTags(testTagPolicy, resources)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Compliant)
+}
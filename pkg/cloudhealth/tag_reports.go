@@ -0,0 +1,102 @@
+package cloudhealth
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TagDimensionPrefix is prepended to a tag key to form the name of the OLAP report dimension grouping by that tag.
+const TagDimensionPrefix = "AWS-Tag-"
+
+// UntaggedMember is the name of the dimension member grouping resources without the tag.
+const UntaggedMember = "untagged"
+
+// ReportTag represents a tag key found in the dimensions of a report, with its values.
+type ReportTag struct {
+	Key         string
+	Values      []string
+	HasUntagged bool
+}
+
+// TagDimension returns the name of the OLAP report dimension grouping by the tag key.
+func TagDimension(key string) string {
+	return TagDimensionPrefix + key
+}
+
+// TagFilter returns an OLAP report filter selecting resources tagged with the key and one of the values.
+// Use UntaggedMember as a value to include resources without the tag.
+func TagFilter(key string, values ...string) OLAPFilter {
+	return OLAPFilter{Dimension: TagDimension(key), Members: values}
+}
+
+// UntaggedFilter returns an OLAP report filter selecting only resources without the tag key.
+func UntaggedFilter(key string) OLAPFilter {
+	return OLAPFilter{Dimension: TagDimension(key), Members: []string{UntaggedMember}}
+}
+
+// ExcludeUntaggedFilter returns an OLAP report filter rejecting resources without the tag key.
+func ExcludeUntaggedFilter(key string) OLAPFilter {
+	return OLAPFilter{Dimension: TagDimension(key), Reject: true, Members: []string{UntaggedMember}}
+}
+
+// Tags returns the tag keys and values found in the dimensions of the report, sorted by key.
+func (r *OLAPReport) Tags() []ReportTag {
+	var tags []ReportTag
+	for _, dimension := range r.Dimensions {
+		if !strings.HasPrefix(dimension.Name, TagDimensionPrefix) {
+			continue
+		}
+
+		tag := ReportTag{Key: strings.TrimPrefix(dimension.Name, TagDimensionPrefix)}
+		for _, member := range dimension.Members {
+			switch member.Name {
+			case UntaggedMember:
+				tag.HasUntagged = true
+			case TotalMember:
+			default:
+				tag.Values = append(tag.Values, member.Name)
+			}
+		}
+		sort.Strings(tag.Values)
+		tags = append(tags, tag)
+	}
+
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Key < tags[j].Key
+	})
+
+	return tags
+}
+
+// CostByTag sums a measure of the report by value of the tag key. Resources without the tag
+// are summed under UntaggedMember.
+func (r *OLAPReport) CostByTag(key string, measure string) (map[string]float64, error) {
+	tagDimension := -1
+	for i, dimension := range r.Dimensions {
+		if dimension.Name == TagDimension(key) {
+			tagDimension = i
+			break
+		}
+	}
+	if tagDimension < 0 {
+		return nil, fmt.Errorf("the report doesn't contain the `%s` dimension", TagDimension(key))
+	}
+
+	cells, err := cellsForMeasure(r, measure)
+	if err != nil {
+		return nil, err
+	}
+
+	costs := make(map[string]float64)
+	for _, cell := range cells {
+		// Skip cells that are aggregates of other cells to avoid double counting
+		if isAggregate(cell.members) {
+			continue
+		}
+
+		costs[cell.members[tagDimension].Name] += cell.value
+	}
+
+	return costs, nil
+}
@@ -0,0 +1,55 @@
+package cloudhealth
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const tagCostReport = `{
+	"dimensions": [
+		{"AWS-Tag-team": [{"name": "total"}, {"name": "a"}, {"name": "a/b"}, {"name": "untagged"}]},
+		{"AWS-Tag-app": [{"name": "b/c"}, {"name": "c"}]}
+	],
+	"measures": [{"name": "cost"}],
+	"data": [
+		[[10.0], [20.0]],
+		[[1.0], [2.0]],
+		[[3.0], [4.0]],
+		[[6.0], [14.0]]
+	]
+}`
+
+func TestTagFilters(t *testing.T) {
+	assert.Equal(t, "AWS-Tag-env:select:prod,untagged", TagFilter("env", "prod", UntaggedMember).String())
+	assert.Equal(t, "AWS-Tag-env:select:untagged", UntaggedFilter("env").String())
+	assert.Equal(t, "AWS-Tag-env:reject:untagged", ExcludeUntaggedFilter("env").String())
+}
+
+func TestReportTags(t *testing.T) {
+	var report OLAPReport
+	assert.NoError(t, json.Unmarshal([]byte(tagCostReport), &report))
+
+	assert.Equal(t, []ReportTag{
+		{Key: "app", Values: []string{"b/c", "c"}},
+		{Key: "team", Values: []string{"a", "a/b"}, HasUntagged: true},
+	}, report.Tags())
+}
+
+func TestCostByTag(t *testing.T) {
+	var report OLAPReport
+	assert.NoError(t, json.Unmarshal([]byte(tagCostReport), &report))
+
+	// Members containing "/", such as a/b and c, and a and b/c, must not be merged
+	costs, err := report.CostByTag("team", MeasureCost)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float64{"a": 3, "a/b": 7, UntaggedMember: 20}, costs)
+
+	costs, err = report.CostByTag("app", MeasureCost)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float64{"b/c": 10, "c": 20}, costs)
+
+	_, err = report.CostByTag("owner", MeasureCost)
+	assert.EqualError(t, err, "the report doesn't contain the `AWS-Tag-owner` dimension")
+}
@@ -0,0 +1,203 @@
+package cloudhealth
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Tag represents a key/value tag of a CloudHealth resource.
+type Tag struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Tags represents the tags of a CloudHealth resource, in the order returned by the API.
+type Tags []Tag
+
+// TagSelector represents a condition on tags: a key that must exist (`env`) or not (`!env`),
+// or a value it must have (`env=prod`) or not (`env!=prod`).
+type TagSelector struct {
+	Key    string
+	Value  string
+	Negate bool
+	Exists bool
+}
+
+// Get returns the value of the tag with the given key.
+func (t Tags) Get(key string) (string, bool) {
+	for _, tag := range t {
+		if tag.Key == key {
+			return tag.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// Set sets the value of the tag with the given key, adding it if missing.
+func (t *Tags) Set(key string, value string) {
+	for i, tag := range *t {
+		if tag.Key == key {
+			(*t)[i].Value = value
+			return
+		}
+	}
+
+	*t = append(*t, Tag{Key: key, Value: value})
+}
+
+// Delete removes the tag with the given key, if any.
+func (t *Tags) Delete(key string) {
+	kept := (*t)[:0]
+	for _, tag := range *t {
+		if tag.Key != key {
+			kept = append(kept, tag)
+		}
+	}
+
+	*t = kept
+}
+
+// Merge returns the tags with the tags of other added, values of other taking precedence.
+func (t Tags) Merge(other Tags) Tags {
+	merged := append(Tags{}, t...)
+	for _, tag := range other {
+		merged.Set(tag.Key, tag.Value)
+	}
+
+	return merged
+}
+
+// Diff returns the tags of other missing from t, those with a different value in other, and those missing from other.
+func (t Tags) Diff(other Tags) (added Tags, changed Tags, removed Tags) {
+	for _, tag := range other {
+		value, ok := t.Get(tag.Key)
+		if !ok {
+			added = append(added, tag)
+		} else if value != tag.Value {
+			changed = append(changed, tag)
+		}
+	}
+
+	for _, tag := range t {
+		if _, ok := other.Get(tag.Key); !ok {
+			removed = append(removed, tag)
+		}
+	}
+
+	return added, changed, removed
+}
+
+// Map returns the tags keyed by tag key.
+func (t Tags) Map() map[string]string {
+	tags := make(map[string]string, len(t))
+	for _, tag := range t {
+		tags[tag.Key] = tag.Value
+	}
+
+	return tags
+}
+
+// TagsFromMap returns the tags of the map, sorted by key.
+func TagsFromMap(tags map[string]string) Tags {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make(Tags, len(keys))
+	for i, key := range keys {
+		result[i] = Tag{Key: key, Value: tags[key]}
+	}
+
+	return result
+}
+
+// ParseTagSelectors parses comma-separated selectors, such as `env=prod,!temporary`.
+func ParseTagSelectors(selectors string) ([]TagSelector, error) {
+	var parsed []TagSelector
+	for _, selector := range strings.Split(selectors, ",") {
+		selector = strings.TrimSpace(selector)
+		if selector == "" {
+			continue
+		}
+
+		var tagSelector TagSelector
+		switch {
+		case strings.Contains(selector, "!="):
+			parts := strings.SplitN(selector, "!=", 2)
+			tagSelector = TagSelector{Key: strings.TrimSpace(parts[0]), Value: strings.TrimSpace(parts[1]), Negate: true}
+		case strings.Contains(selector, "="):
+			parts := strings.SplitN(selector, "=", 2)
+			tagSelector = TagSelector{Key: strings.TrimSpace(parts[0]), Value: strings.TrimSpace(parts[1])}
+		case strings.HasPrefix(selector, "!"):
+			tagSelector = TagSelector{Key: strings.TrimSpace(selector[1:]), Exists: true, Negate: true}
+		default:
+			tagSelector = TagSelector{Key: selector, Exists: true}
+		}
+
+		if tagSelector.Key == "" {
+			return nil, fmt.Errorf("invalid tag selector `%s`", selector)
+		}
+		parsed = append(parsed, tagSelector)
+	}
+
+	return parsed, nil
+}
+
+// Matches reports whether the tags satisfy the selector.
+func (s TagSelector) Matches(tags Tags) bool {
+	value, ok := tags.Get(s.Key)
+	if s.Exists {
+		return ok != s.Negate
+	}
+
+	return (ok && value == s.Value) != s.Negate
+}
+
+// Matches reports whether the tags satisfy all the selectors.
+func (t Tags) Matches(selectors ...TagSelector) bool {
+	for _, selector := range selectors {
+		if !selector.Matches(t) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FilterByTags returns the Customers whose tags satisfy the comma-separated selectors, such as `env=prod,!temporary`.
+func (c *Customers) FilterByTags(selectors string) ([]Customer, error) {
+	parsed, err := ParseTagSelectors(selectors)
+	if err != nil {
+		return nil, err
+	}
+
+	var customers []Customer
+	for _, customer := range c.Customers {
+		if customer.Tags.Matches(parsed...) {
+			customers = append(customers, customer)
+		}
+	}
+
+	return customers, nil
+}
+
+// FilterByTags returns the AWS Accounts whose tags satisfy the comma-separated selectors, such as `env=prod,!temporary`.
+func (a *AwsAccounts) FilterByTags(selectors string) ([]AwsAccount, error) {
+	parsed, err := ParseTagSelectors(selectors)
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []AwsAccount
+	for _, account := range a.AwsAccounts {
+		if account.Tags.Matches(parsed...) {
+			accounts = append(accounts, account)
+		}
+	}
+
+	return accounts, nil
+}
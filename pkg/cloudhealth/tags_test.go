@@ -0,0 +1,60 @@
+package cloudhealth
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTags(t *testing.T) {
+	var account AwsAccount
+	assert.NoError(t, json.Unmarshal([]byte(`{"name": "prod", "tags": [{"key": "env", "value": "prod"}, {"key": "team", "value": "payments"}]}`), &account))
+
+	value, ok := account.Tags.Get("team")
+	assert.True(t, ok)
+	assert.Equal(t, "payments", value)
+
+	account.Tags.Set("team", "billing")
+	account.Tags.Set("owner", "alice")
+	account.Tags.Delete("env")
+	assert.Equal(t, Tags{{Key: "team", Value: "billing"}, {Key: "owner", Value: "alice"}}, account.Tags)
+
+	encoded, err := json.Marshal(account.Tags)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"key": "team", "value": "billing"}, {"key": "owner", "value": "alice"}]`, string(encoded))
+
+	merged := account.Tags.Merge(TagsFromMap(map[string]string{"owner": "bob", "env": "prod"}))
+	assert.Equal(t, map[string]string{"team": "billing", "owner": "bob", "env": "prod"}, merged.Map())
+
+	added, changed, removed := account.Tags.Diff(Tags{{Key: "owner", Value: "bob"}, {Key: "env", Value: "prod"}})
+	assert.Equal(t, Tags{{Key: "env", Value: "prod"}}, added)
+	assert.Equal(t, Tags{{Key: "owner", Value: "bob"}}, changed)
+	assert.Equal(t, Tags{{Key: "team", Value: "billing"}}, removed)
+}
+
+func TestFilterByTags(t *testing.T) {
+	accounts := &AwsAccounts{AwsAccounts: []AwsAccount{
+		{Name: "prod", Tags: Tags{{Key: "env", Value: "prod"}}},
+		{Name: "staging", Tags: Tags{{Key: "env", Value: "staging"}, {Key: "temporary", Value: "yes"}}},
+		{Name: "sandbox"},
+	}}
+
+	filtered, err := accounts.FilterByTags("env!=prod, !temporary")
+	assert.NoError(t, err)
+	if assert.Len(t, filtered, 1) {
+		assert.Equal(t, "sandbox", filtered[0].Name)
+	}
+
+	filtered, err = accounts.FilterByTags("env")
+	assert.NoError(t, err)
+	assert.Len(t, filtered, 2)
+
+	customers := &Customers{Customers: []Customer{{Name: "acme", Tags: Tags{{Key: "tier", Value: "gold"}}}, {Name: "globex"}}}
+	matched, err := customers.FilterByTags("tier=gold")
+	assert.NoError(t, err)
+	assert.Len(t, matched, 1)
+
+	_, err = customers.FilterByTags("=gold")
+	assert.EqualError(t, err, "invalid tag selector `=gold`")
+}
@@ -0,0 +1,187 @@
+package cloudhealth
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Interval represents the granularity of the time dimension of a report.
+type Interval string
+
+// Intervals supported by CloudHealth reports.
+const (
+	IntervalHourly  Interval = "hourly"
+	IntervalDaily   Interval = "daily"
+	IntervalWeekly  Interval = "weekly"
+	IntervalMonthly Interval = "monthly"
+)
+
+// Validate checks that the Interval is one supported by CloudHealth.
+func (i Interval) Validate() error {
+	switch i {
+	case IntervalHourly, IntervalDaily, IntervalWeekly, IntervalMonthly:
+		return nil
+	case "":
+		return errors.New("the `interval` property is required and cannot be blank")
+	default:
+		return fmt.Errorf("unknown interval `%s`, expected one of hourly, daily, weekly or monthly", string(i))
+	}
+}
+
+// timeRangeKind identifies how a TimeRange was defined.
+type timeRangeKind int
+
+const (
+	timeRangeUnset timeRangeKind = iota
+	timeRangeAbsolute
+	timeRangeLastMonths
+	timeRangeMonthToDate
+	timeRangeIndices
+)
+
+// TimeRange represents the time filter of a report. Use one of NewAbsoluteTimeRange, LastMonths,
+// MonthToDate or TimeIndices to build one; the zero value is not a valid TimeRange.
+//
+// CloudHealth encodes time filters as indices of the time dimension relative to the current
+// period: 0 is the current hour, day, week or month depending on the interval, -1 the period
+// before it, and so on. Weeks start on Sunday.
+type TimeRange struct {
+	kind    timeRangeKind
+	from    time.Time
+	to      time.Time
+	months  int
+	indices []int
+}
+
+// NewAbsoluteTimeRange returns a TimeRange covering the periods from the one containing from up
+// to and including the one containing to. An end in the future is clamped to now.
+func NewAbsoluteTimeRange(from time.Time, to time.Time) TimeRange {
+	return TimeRange{kind: timeRangeAbsolute, from: from.UTC(), to: to.UTC()}
+}
+
+// LastMonths returns a TimeRange covering the last n complete months, excluding the current month.
+func LastMonths(n int) TimeRange {
+	return TimeRange{kind: timeRangeLastMonths, months: n}
+}
+
+// MonthToDate returns a TimeRange covering the current month up to now.
+func MonthToDate() TimeRange {
+	return TimeRange{kind: timeRangeMonthToDate}
+}
+
+// TimeIndices returns a TimeRange using CloudHealth's time-index encoding directly.
+func TimeIndices(indices ...int) TimeRange {
+	return TimeRange{kind: timeRangeIndices, indices: indices}
+}
+
+// IsZero reports whether the TimeRange has not been set.
+func (t TimeRange) IsZero() bool {
+	return t.kind == timeRangeUnset
+}
+
+// Validate checks that the TimeRange can be encoded for the given Interval.
+func (t TimeRange) Validate(interval Interval) error {
+	_, err := t.encode(interval, time.Now().UTC())
+	return err
+}
+
+// Encode returns the CloudHealth time-index encoding of the TimeRange for the given Interval,
+// as used in `filters[]=time:select:...`.
+func (t TimeRange) Encode(interval Interval) (string, error) {
+	return t.encode(interval, time.Now().UTC())
+}
+
+// Bounds returns the first and last instants covered by the TimeRange, relative to now.
+// It is not available for ranges built with TimeIndices.
+func (t TimeRange) Bounds() (time.Time, time.Time, error) {
+	return t.bounds(time.Now().UTC())
+}
+
+// bounds resolves the TimeRange into absolute bounds relative to now.
+func (t TimeRange) bounds(now time.Time) (time.Time, time.Time, error) {
+	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	switch t.kind {
+	case timeRangeAbsolute:
+		return t.from, t.to, nil
+	case timeRangeLastMonths:
+		if t.months <= 0 {
+			return time.Time{}, time.Time{}, fmt.Errorf("the number of months must be positive, got %d", t.months)
+		}
+		return startOfMonth.AddDate(0, -t.months, 0), startOfMonth.Add(-time.Nanosecond), nil
+	case timeRangeMonthToDate:
+		return startOfMonth, now, nil
+	case timeRangeIndices:
+		return time.Time{}, time.Time{}, errors.New("time ranges built from time indices have no absolute bounds")
+	default:
+		return time.Time{}, time.Time{}, errors.New("the `time` property is required and cannot be blank")
+	}
+}
+
+// encode returns the CloudHealth time-index encoding of the TimeRange relative to now.
+func (t TimeRange) encode(interval Interval, now time.Time) (string, error) {
+	err := interval.Validate()
+	if err != nil {
+		return "", err
+	}
+
+	indices := t.indices
+	if t.kind == timeRangeIndices {
+		if len(indices) == 0 {
+			return "", errors.New("at least one time index is required")
+		}
+		for _, index := range indices {
+			if index > 0 {
+				return "", fmt.Errorf("time index %d is in the future, indices must be 0 or negative", index)
+			}
+		}
+	} else {
+		from, to, err := t.bounds(now)
+		if err != nil {
+			return "", err
+		}
+		if from.After(to) {
+			return "", fmt.Errorf("the start of the time range (%s) is after its end (%s)", from.Format(time.RFC3339), to.Format(time.RFC3339))
+		}
+		if from.After(now) {
+			return "", fmt.Errorf("the start of the time range (%s) is in the future", from.Format(time.RFC3339))
+		}
+		// Periods after the current one have no data yet, so a range ending in the future stops now
+		if to.After(now) {
+			to = now
+		}
+
+		current := periodIndex(interval, now)
+		indices = nil
+		for i := periodIndex(interval, from); i <= periodIndex(interval, to); i++ {
+			indices = append(indices, int(i-current))
+		}
+	}
+
+	encoded := make([]string, len(indices))
+	for i, index := range indices {
+		encoded[i] = strconv.Itoa(index)
+	}
+
+	return strings.Join(encoded, ","), nil
+}
+
+// periodIndex returns the absolute number of the period containing t for the given Interval.
+func periodIndex(interval Interval, t time.Time) int64 {
+	days := t.Unix() / 86400
+
+	switch interval {
+	case IntervalHourly:
+		return t.Unix() / 3600
+	case IntervalDaily:
+		return days
+	case IntervalWeekly:
+		// 1970-01-01 was a Thursday, shift so that weeks start on Sunday
+		return (days + 4) / 7
+	default:
+		return int64(t.Year())*12 + int64(t.Month()) - 1
+	}
+}
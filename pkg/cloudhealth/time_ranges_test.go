@@ -0,0 +1,127 @@
+package cloudhealth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntervalValidate(t *testing.T) {
+	assert.NoError(t, IntervalWeekly.Validate())
+	assert.EqualError(t, Interval("").Validate(), "the `interval` property is required and cannot be blank")
+	assert.EqualError(t, Interval("yearly").Validate(), "unknown interval `yearly`, expected one of hourly, daily, weekly or monthly")
+}
+
+func TestTimeRangeEncode(t *testing.T) {
+	now := time.Date(2022, 3, 3, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		timeRange TimeRange
+		interval  Interval
+		want      string
+		wantErr   string
+	}{
+		{
+			name:      "last months monthly",
+			timeRange: LastMonths(3),
+			interval:  IntervalMonthly,
+			want:      "-3,-2,-1",
+		},
+		{
+			name:      "last month daily",
+			timeRange: LastMonths(1),
+			interval:  IntervalDaily,
+			want:      "-30,-29,-28,-27,-26,-25,-24,-23,-22,-21,-20,-19,-18,-17,-16,-15,-14,-13,-12,-11,-10,-9,-8,-7,-6,-5,-4,-3",
+		},
+		{
+			name:      "month to date daily",
+			timeRange: MonthToDate(),
+			interval:  IntervalDaily,
+			want:      "-2,-1,0",
+		},
+		{
+			name:      "month to date weekly",
+			timeRange: MonthToDate(),
+			interval:  IntervalWeekly,
+			want:      "0",
+		},
+		{
+			name:      "absolute hourly",
+			timeRange: NewAbsoluteTimeRange(time.Date(2022, 3, 3, 8, 0, 0, 0, time.UTC), time.Date(2022, 3, 3, 9, 59, 0, 0, time.UTC)),
+			interval:  IntervalHourly,
+			want:      "-2,-1",
+		},
+		{
+			name:      "indices",
+			timeRange: TimeIndices(-1, 0),
+			interval:  IntervalMonthly,
+			want:      "-1,0",
+		},
+		{
+			name:      "unset",
+			timeRange: TimeRange{},
+			interval:  IntervalMonthly,
+			wantErr:   "the `time` property is required and cannot be blank",
+		},
+		{
+			name:      "future index",
+			timeRange: TimeIndices(1),
+			interval:  IntervalMonthly,
+			wantErr:   "time index 1 is in the future, indices must be 0 or negative",
+		},
+		{
+			name:      "reversed",
+			timeRange: NewAbsoluteTimeRange(time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)),
+			interval:  IntervalMonthly,
+			wantErr:   "the start of the time range (2022-02-01T00:00:00Z) is after its end (2022-01-01T00:00:00Z)",
+		},
+		{
+			name:      "future end",
+			timeRange: NewAbsoluteTimeRange(time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)),
+			interval:  IntervalMonthly,
+			want:      "-1,0",
+		},
+		{
+			name:      "end of current month daily",
+			timeRange: NewAbsoluteTimeRange(time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, 3, 31, 23, 59, 59, 0, time.UTC)),
+			interval:  IntervalDaily,
+			want:      "-2,-1,0",
+		},
+		{
+			name:      "future start",
+			timeRange: NewAbsoluteTimeRange(time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, 4, 30, 0, 0, 0, 0, time.UTC)),
+			interval:  IntervalMonthly,
+			wantErr:   "the start of the time range (2022-04-01T00:00:00Z) is in the future",
+		},
+		{
+			name:      "no months",
+			timeRange: LastMonths(0),
+			interval:  IntervalMonthly,
+			wantErr:   "the number of months must be positive, got 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.timeRange.encode(tt.interval, now)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetAWSCostHistoryReportValidation(t *testing.T) {
+	c, err := NewClient("apiKey", "http://127.0.0.1:1/")
+	assert.NoError(t, err)
+
+	_, err = c.GetAWSCostHistoryReport(&AWSCostHistoryRequestOptions{Measures: "cost", Interval: "yearly", TimeRange: LastMonths(1)})
+	assert.EqualError(t, err, "unknown interval `yearly`, expected one of hourly, daily, weekly or monthly")
+
+	_, err = c.GetAWSCostHistoryReport(&AWSCostHistoryRequestOptions{Measures: "cost", Interval: IntervalMonthly})
+	assert.EqualError(t, err, "the `time` property is required and cannot be blank")
+}
@@ -0,0 +1,58 @@
+package cloudhealth
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// timestampFormats lists the formats of dates found in CloudHealth responses, tried in order.
+var timestampFormats = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// Timestamp represents a date of a CloudHealth response. It decodes leniently: null, empty or
+// unrecognized dates decode to the zero time rather than failing the whole response, and the
+// zero time encodes to null.
+type Timestamp struct {
+	time.Time
+}
+
+// NewTimestamp returns the Timestamp of t.
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{Time: t}
+}
+
+// UnmarshalJSON decodes a date in any of the formats used by CloudHealth.
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	t.Time = time.Time{}
+
+	var value string
+	if bytes.Equal(data, []byte("null")) || json.Unmarshal(data, &value) != nil || value == "" {
+		return nil
+	}
+
+	for _, format := range timestampFormats {
+		parsed, err := time.Parse(format, value)
+		if err == nil {
+			t.Time = parsed
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes the date in the RFC 3339 format, or null when it is zero.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(t.Time.Format(time.RFC3339Nano))
+}